@@ -18,6 +18,7 @@ package v1
 
 import (
 	conditions "github.com/openshift/custom-resource-status/conditions/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -48,8 +49,81 @@ type ClusterStatus struct {
 	// +patchMergeKey=type
 	// +patchStrategy=merge
 	Conditions []conditions.Condition `json:"conditions,omitempty"  patchStrategy:"merge" patchMergeKey:"type"`
+
+	// ObservedGeneration is the most recent generation observed by the
+	// controller.
+	// +kubebuilder:validation:Optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastReconcileTime is the last time the controller successfully
+	// reconciled a change to the object.
+	// +kubebuilder:validation:Optional
+	LastReconcileTime *metav1.Time `json:"lastReconcileTime,omitempty"`
+
+	// OperatorVersion is the version of the operator that last reconciled
+	// the spec of the cluster.
+	// +kubebuilder:validation:Optional
+	OperatorVersion string `json:"operatorVersion,omitempty"`
+
+	// RelatedObjects is a list of objects managed by the operator for the
+	// cluster: the child objects and the workloads they produce.
+	// +kubebuilder:validation:Optional
+	RelatedObjects []corev1.ObjectReference `json:"relatedObjects,omitempty"`
+
+	// Versions is the list of operator and component versions the cluster
+	// was last successfully upgraded to.
+	// +kubebuilder:validation:Optional
+	// +patchMergeKey=name
+	// +patchStrategy=merge
+	Versions []ComponentVersion `json:"versions,omitempty"  patchStrategy:"merge" patchMergeKey:"name"`
+}
+
+// Names of the components reported in ClusterStatus.Versions.
+const (
+	OperatorComponent = "operator"
+	AppComponent      = "app"
+	SidecarAComponent = "sidecarA"
+	SidecarBComponent = "sidecarB"
+)
+
+// ReasonDuplicateCluster is the condition reason set on a Cluster that is
+// ignored because another Cluster already manages its namespace.
+const ReasonDuplicateCluster = "DuplicateCluster"
+
+// Reasons of the Progressing condition of a Cluster.
+const (
+	// ReasonUpgrading is set while the components are not at the desired
+	// versions.
+	ReasonUpgrading = "Upgrading"
+
+	// ReasonUpgradeComplete is set once all the components are at the
+	// desired versions.
+	ReasonUpgradeComplete = "UpgradeComplete"
+)
+
+// PausedAnnotation pauses the reconciliation of the components of a Cluster
+// when set to "true". The status of the Cluster is still kept up to date.
+const PausedAnnotation = "darkowlzz.space/paused"
+
+// ClusterNameLabel holds the name of the Cluster on its children. The
+// controllers of the children set it on the workloads they create and on
+// their pod templates, the operator only watches the labeled workloads.
+const ClusterNameLabel = "darkowlzz.space/cluster"
+
+// ReasonPaused is the condition reason set on a paused Cluster.
+const ReasonPaused = "Paused"
+
+// ComponentVersion is the version of a component of the cluster.
+type ComponentVersion struct {
+	// Name is the name of the component.
+	Name string `json:"name"`
+
+	// Version is the version of the component. For the cluster components,
+	// this is the container image reference.
+	Version string `json:"version"`
 }
 
+// +genclient
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 
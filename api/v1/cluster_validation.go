@@ -0,0 +1,71 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"k8s.io/apimachinery/pkg/api/validation"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// longestChildPrefix is the longest prefix of the names of the children of a
+// Cluster, which are named after the Cluster.
+const longestChildPrefix = "sidecara-"
+
+// logLevels are the valid log levels of a Cluster.
+var logLevels = []string{"info", "debug", "error"}
+
+// Validate checks the rules of the API that only depend on the Cluster
+// itself, so that a Cluster can be validated offline. The rules depending on
+// the other objects, like the single Cluster per namespace, are checked by
+// the webhook.
+func (r *Cluster) Validate() field.ErrorList {
+	var errs field.ErrorList
+
+	namePath := field.NewPath("metadata", "name")
+	if r.Name == "" {
+		errs = append(errs, field.Required(namePath, ""))
+	} else {
+		for _, msg := range validation.NameIsDNSSubdomain(r.Name, false) {
+			errs = append(errs, field.Invalid(namePath, r.Name, msg))
+		}
+		if max := utilvalidation.DNS1123SubdomainMaxLength - len(longestChildPrefix); len(r.Name) > max {
+			errs = append(errs, field.TooLong(namePath, r.Name, max))
+		}
+	}
+	if r.Namespace != "" {
+		for _, msg := range validation.ValidateNamespaceName(r.Namespace, false) {
+			errs = append(errs, field.Invalid(field.NewPath("metadata", "namespace"), r.Namespace, msg))
+		}
+	}
+	errs = append(errs, metav1validation.ValidateLabels(r.Labels, field.NewPath("metadata", "labels"))...)
+
+	if r.Spec.LogLevel != "" && !contains(logLevels, r.Spec.LogLevel) {
+		errs = append(errs, field.NotSupported(field.NewPath("spec", "logLevel"), r.Spec.LogLevel, logLevels))
+	}
+	return errs
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var clusterlog = logf.Log.WithName("cluster-resource")
+
+// clusterReader is used by the webhook to look up the existing Clusters. It
+// reads from the API server rather than the manager cache, which only holds
+// the watched namespaces and may miss a Cluster that was just created.
+var clusterReader client.Reader
+
+func (r *Cluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	clusterReader = mgr.GetAPIReader()
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create,path=/validate-darkowlzz-space-v1-cluster,mutating=false,failurePolicy=fail,groups=darkowlzz.space,resources=clusters,versions=v1,name=vcluster.kb.io
+
+var _ webhook.Validator = &Cluster{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *Cluster) ValidateCreate() error {
+	clusterlog.Info("validate create", "name", r.Name)
+
+	if errs := r.Validate(); len(errs) > 0 {
+		return apierrors.NewInvalid(GroupVersion.WithKind("Cluster").GroupKind(), r.Name, errs)
+	}
+
+	// Only one Cluster is allowed per namespace. A Cluster being deleted no
+	// longer counts, as for the reconciler, so that it can be replaced.
+	var clusters ClusterList
+	if err := clusterReader.List(context.TODO(), &clusters, client.InNamespace(r.Namespace)); err != nil {
+		return err
+	}
+	for _, c := range clusters.Items {
+		if c.DeletionTimestamp != nil {
+			continue
+		}
+		if c.Name != r.Name {
+			return fmt.Errorf("Cluster %q already exists in namespace %q, only one Cluster is allowed per namespace", c.Name, r.Namespace)
+		}
+	}
+	return nil
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *Cluster) ValidateUpdate(old runtime.Object) error {
+	return nil
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *Cluster) ValidateDelete() error {
+	return nil
+}
@@ -0,0 +1,109 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+)
+
+// TestValidateCreateDuplicate checks that a second Cluster is rejected in a
+// namespace, unless the existing one is being deleted.
+func TestValidateCreateDuplicate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	existing := &Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "old"}}
+	replacement := &Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "new"}}
+
+	clusterReader = fake.NewFakeClientWithScheme(scheme, existing.DeepCopy())
+	err := replacement.ValidateCreate()
+	if err == nil || !strings.Contains(err.Error(), "only one Cluster is allowed per namespace") {
+		t.Errorf("expected the duplicate Cluster to be rejected, got %v", err)
+	}
+
+	deleting := existing.DeepCopy()
+	now := metav1.Now()
+	deleting.DeletionTimestamp = &now
+	clusterReader = fake.NewFakeClientWithScheme(scheme, deleting)
+	if err := replacement.ValidateCreate(); err != nil {
+		t.Errorf("expected the replacement of a deleting Cluster to be accepted, got %v", err)
+	}
+}
+
+// TestValidateCreateUnwatchedNamespace checks that the Clusters are looked up
+// in the namespaces that the manager does not watch.
+func TestValidateCreateUnwatchedNamespace(t *testing.T) {
+	testEnv := &envtest.Environment{
+		CRDDirectoryPaths: []string{filepath.Join("..", "..", "config", "crd", "bases")},
+	}
+	cfg, err := testEnv.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := testEnv.Stop(); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:             scheme,
+		MetricsBindAddress: "0",
+		NewCache:           cache.MultiNamespacedCacheBuilder([]string{"tenant-a"}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := (&Cluster{}).SetupWebhookWithManager(mgr); err != nil {
+		t.Fatal(err)
+	}
+
+	first := &Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "first"}}
+	if err := first.ValidateCreate(); err != nil {
+		t.Fatalf("expected the first Cluster of an unwatched namespace to be accepted, got %v", err)
+	}
+
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Create(context.Background(), first); err != nil {
+		t.Fatal(err)
+	}
+	// The API server is read directly, the new Cluster is seen at once.
+	second := &Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "second"}}
+	err = second.ValidateCreate()
+	if err == nil || !strings.Contains(err.Error(), "only one Cluster is allowed per namespace") {
+		t.Errorf("expected the duplicate Cluster to be rejected, got %v", err)
+	}
+}
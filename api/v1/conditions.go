@@ -0,0 +1,96 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	conditions "github.com/openshift/custom-resource-status/conditions/v1"
+)
+
+// ConditionReady is the condition summarizing the Available, Progressing and
+// Degraded conditions of an object, following the conventions of kstatus. It
+// is True once the object is available and neither progressing nor degraded.
+// Whether it reflects the latest spec of the object is given by the
+// observedGeneration of the status.
+const ConditionReady conditions.ConditionType = "Ready"
+
+const (
+	// ReasonReady is the reason of a True Ready condition.
+	ReasonReady = "Ready"
+
+	// ReasonReconciled is the reason of the conditions of a child of a
+	// Cluster that has observed its latest spec.
+	ReasonReconciled = "Reconciled"
+)
+
+// ConditionsAccessor gives access to the status conditions of an object. It
+// is implemented by all the kinds of the API, so that the logic handling
+// conditions can be shared between them.
+// +kubebuilder:object:generate=false
+type ConditionsAccessor interface {
+	// GetConditions returns the status conditions of the object.
+	GetConditions() []conditions.Condition
+
+	// SetConditions sets the status conditions of the object.
+	SetConditions([]conditions.Condition)
+}
+
+var (
+	_ ConditionsAccessor = &Cluster{}
+	_ ConditionsAccessor = &App{}
+	_ ConditionsAccessor = &SidecarA{}
+	_ ConditionsAccessor = &SidecarB{}
+)
+
+// GetConditions implements ConditionsAccessor.
+func (c *Cluster) GetConditions() []conditions.Condition {
+	return c.Status.Conditions
+}
+
+// SetConditions implements ConditionsAccessor.
+func (c *Cluster) SetConditions(conds []conditions.Condition) {
+	c.Status.Conditions = conds
+}
+
+// GetConditions implements ConditionsAccessor.
+func (a *App) GetConditions() []conditions.Condition {
+	return a.Status.Conditions
+}
+
+// SetConditions implements ConditionsAccessor.
+func (a *App) SetConditions(conds []conditions.Condition) {
+	a.Status.Conditions = conds
+}
+
+// GetConditions implements ConditionsAccessor.
+func (s *SidecarA) GetConditions() []conditions.Condition {
+	return s.Status.Conditions
+}
+
+// SetConditions implements ConditionsAccessor.
+func (s *SidecarA) SetConditions(conds []conditions.Condition) {
+	s.Status.Conditions = conds
+}
+
+// GetConditions implements ConditionsAccessor.
+func (s *SidecarB) GetConditions() []conditions.Condition {
+	return s.Status.Conditions
+}
+
+// SetConditions implements ConditionsAccessor.
+func (s *SidecarB) SetConditions(conds []conditions.Condition) {
+	s.Status.Conditions = conds
+}
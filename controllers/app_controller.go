@@ -17,12 +17,15 @@ limitations under the License.
 package controllers
 
 import (
-	"context"
-
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	darkowlzzspacev1 "github.com/darkowlzz/hco/api/v1"
 )
@@ -37,17 +40,45 @@ type AppReconciler struct {
 // +kubebuilder:rbac:groups=darkowlzz.space,resources=apps,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=darkowlzz.space,resources=apps/status,verbs=get;update;patch
 
-func (r *AppReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
-	_ = context.Background()
-	_ = r.Log.WithValues("app", req.NamespacedName)
+func (r *AppReconciler) Reconcile(req ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := startReconcileSpan("App", req)
+	defer func() { endSpan(span, err) }()
+	log := r.Log.WithValues("app", req.NamespacedName)
+
+	var app darkowlzzspacev1.App
+	fetchCtx, fetchSpan := tracer.Start(ctx, "fetch")
+	err = r.Get(fetchCtx, req.NamespacedName, &app)
+	endSpan(fetchSpan, client.IgnoreNotFound(err))
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	setClusterAttributes(span, &app)
 
 	// your logic here
 
+	// Record the generation that has been processed.
+	base := app.DeepCopy()
+	setReconciledConditions(&app)
+	if app.Status.ObservedGeneration != app.Generation ||
+		!equality.Semantic.DeepEqual(base.Status, app.Status) {
+		now := metav1.Now()
+		app.Status.ObservedGeneration = app.Generation
+		app.Status.LastReconcileTime = &now
+		statusCtx, statusSpan := tracer.Start(ctx, "updateStatus")
+		err = r.Status().Patch(statusCtx, &app, statusPatch(base), client.FieldOwner(FieldManager))
+		endSpan(statusSpan, err)
+		if err != nil {
+			return requeueOnConflict(err)
+		}
+		log.Info("App reconciled", "generation", app.Generation)
+	}
+
 	return ctrl.Result{}, nil
 }
 
-func (r *AppReconciler) SetupWithManager(mgr ctrl.Manager) error {
+func (r *AppReconciler) SetupWithManager(mgr ctrl.Manager, options controller.Options) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&darkowlzzspacev1.App{}).
+		For(&darkowlzzspacev1.App{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithOptions(options).
 		Complete(r)
 }
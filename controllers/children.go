@@ -0,0 +1,136 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	darkowlzzspacev1 "github.com/darkowlzz/hco/api/v1"
+)
+
+// childObject is a child of a Cluster.
+type childObject interface {
+	metav1.Object
+	runtime.Object
+}
+
+// desiredChild is a child of a Cluster along with the mutation setting its
+// desired spec.
+type desiredChild struct {
+	component string
+	object    childObject
+	mutate    func()
+}
+
+// newChildren returns the children of cluster, holding only their names.
+func newChildren(cluster *darkowlzzspacev1.Cluster) (*darkowlzzspacev1.App, *darkowlzzspacev1.SidecarA, *darkowlzzspacev1.SidecarB) {
+	appInstance := &darkowlzzspacev1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-" + cluster.Name,
+			Namespace: cluster.Namespace,
+		},
+	}
+	sidecarA := &darkowlzzspacev1.SidecarA{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sidecara-" + cluster.Name,
+			Namespace: cluster.Namespace,
+		},
+	}
+	sidecarB := &darkowlzzspacev1.SidecarB{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sidecarb-" + cluster.Name,
+			Namespace: cluster.Namespace,
+		},
+	}
+	return appInstance, sidecarA, sidecarB
+}
+
+// desiredChildren returns the mutations setting the desired spec of the
+// children of cluster.
+func desiredChildren(cluster *darkowlzzspacev1.Cluster, appInstance *darkowlzzspacev1.App, sidecarA *darkowlzzspacev1.SidecarA, sidecarB *darkowlzzspacev1.SidecarB) []desiredChild {
+	return []desiredChild{
+		{
+			component: darkowlzzspacev1.AppComponent,
+			object:    appInstance,
+			mutate:    func() { appInstance.Spec.Image = cluster.Spec.Images.App },
+		},
+		{
+			component: darkowlzzspacev1.SidecarAComponent,
+			object:    sidecarA,
+			mutate:    func() { sidecarA.Spec.Image = cluster.Spec.Images.SidecarA },
+		},
+		{
+			component: darkowlzzspacev1.SidecarBComponent,
+			object:    sidecarB,
+			mutate:    func() { sidecarB.Spec.Image = cluster.Spec.Images.SidecarB },
+		},
+	}
+}
+
+// setDesiredState sets the desired state of a child of cluster, with
+// cluster as its controller.
+func setDesiredState(cluster *darkowlzzspacev1.Cluster, child desiredChild, scheme *runtime.Scheme) error {
+	gvk, err := apiutil.GVKForObject(child.object, scheme)
+	if err != nil {
+		return err
+	}
+	child.object.GetObjectKind().SetGroupVersionKind(gvk)
+	propagateLabels(child.object, cluster.Labels)
+	propagateLabels(child.object, map[string]string{darkowlzzspacev1.ClusterNameLabel: cluster.Name})
+	child.mutate()
+	return controllerutil.SetControllerReference(cluster, child.object, scheme)
+}
+
+// RenderChildren returns the children of cluster in the desired state
+// applied by the ClusterReconciler, without contacting the API server. The
+// workloads of the children are created by their own controllers and are
+// not included. A Cluster that has not been created has no UID, the owner
+// reference to it is then left out of the children since the API server
+// rejects an owner reference without a UID.
+func RenderChildren(cluster *darkowlzzspacev1.Cluster, scheme *runtime.Scheme) ([]runtime.Object, error) {
+	appInstance, sidecarA, sidecarB := newChildren(cluster)
+	var objs []runtime.Object
+	for _, child := range desiredChildren(cluster, appInstance, sidecarA, sidecarB) {
+		if err := setDesiredState(cluster, child, scheme); err != nil {
+			return nil, err
+		}
+		if cluster.UID == "" {
+			child.object.SetOwnerReferences(nil)
+		}
+		objs = append(objs, child.object)
+	}
+	return objs, nil
+}
+
+// propagateLabels copies the given labels onto obj, leaving any other labels
+// of obj untouched.
+func propagateLabels(obj metav1.Object, labels map[string]string) {
+	if len(labels) == 0 {
+		return
+	}
+	objLabels := obj.GetLabels()
+	if objLabels == nil {
+		objLabels = map[string]string{}
+	}
+	for k, v := range labels {
+		objLabels[k] = v
+	}
+	obj.SetLabels(objLabels)
+}
@@ -18,119 +18,550 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"sort"
 
 	"github.com/go-logr/logr"
 	conditions "github.com/openshift/custom-resource-status/conditions/v1"
+	objectreferences "github.com/openshift/custom-resource-status/objectreferences/v1"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	darkowlzzspacev1 "github.com/darkowlzz/hco/api/v1"
+	"github.com/darkowlzz/hco/pkg/version"
 )
 
+// FieldManager is the field manager of the changes made by the operator.
+const FieldManager = "hco-controller-manager"
+
 // ClusterReconciler reconciles a Cluster object
 type ClusterReconciler struct {
 	client.Client
 	Log    logr.Logger
 	Scheme *runtime.Scheme
+
+	// WatchNamespaces are the namespaces watched by the manager, all
+	// namespaces when empty.
+	WatchNamespaces []string
+
+	workloads *workloadInformers
 }
 
 // +kubebuilder:rbac:groups=darkowlzz.space,resources=clusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=darkowlzz.space,resources=clusters/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=darkowlzz.space,resources=clusters/finalizers,verbs=update
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=services;configmaps,verbs=get;list;watch
 
-func (r *ClusterReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
-	ctx := context.Background()
+func (r *ClusterReconciler) Reconcile(req ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := startReconcileSpan("Cluster", req)
+	defer func() { endSpan(span, err) }()
 	log := r.Log.WithValues("cluster", req.NamespacedName)
 
 	// your logic here
 	log.Info("Cluster reconciling...")
 
 	var cluster darkowlzzspacev1.Cluster
-	if err := r.Get(ctx, req.NamespacedName, &cluster); err != nil {
+	fetchCtx, fetchSpan := tracer.Start(ctx, "fetch")
+	err = r.Get(fetchCtx, req.NamespacedName, &cluster)
+	endSpan(fetchSpan, client.IgnoreNotFound(err))
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			deleteClusterMetrics(req.NamespacedName)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	init := true
-	if conditions.IsStatusConditionTrue(cluster.Status.Conditions, conditions.ConditionAvailable) {
-		init = false
+	origStatus := cluster.Status.DeepCopy()
+
+	// Only one Cluster is allowed per namespace. The oldest Cluster manages
+	// the namespace, any other Cluster is only marked as a duplicate.
+	primary, err := r.primaryCluster(ctx, cluster.Namespace)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if primary != nil && primary.Name != cluster.Name {
+		return r.markDuplicate(ctx, &cluster, origStatus, primary.Name)
+	}
+	if degraded := conditions.FindStatusCondition(cluster.Status.Conditions, conditions.ConditionDegraded); degraded != nil &&
+		degraded.Reason == darkowlzzspacev1.ReasonDuplicateCluster {
+		conditions.RemoveStatusCondition(&cluster.Status.Conditions, conditions.ConditionDegraded)
 	}
 
-	// Initialize the cluster if uninitialized.
-	if init {
-		// Create an owner reference using the cluster.
-		ownerRef := metav1.OwnerReference{
-			APIVersion: cluster.APIVersion,
-			Kind:       cluster.Kind,
-			Name:       cluster.Name,
-			UID:        cluster.UID,
-		}
-
-		// Create App, SidecarA and SidecarB with cluster as the owner
-		// reference.
-		appInstance := &darkowlzzspacev1.App{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:            "app-" + cluster.Name,
-				Labels:          cluster.Labels,
-				Namespace:       cluster.Namespace,
-				OwnerReferences: []metav1.OwnerReference{ownerRef},
-			},
-			Spec: darkowlzzspacev1.AppSpec{
-				Image: cluster.Spec.Images.App,
-			},
-		}
-		if err := r.Create(ctx, appInstance); err != nil {
-			log.Info("failed to create app", "error", err)
-		}
-
-		sidecarA := &darkowlzzspacev1.SidecarA{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:            "sidecara-" + cluster.Name,
-				Labels:          cluster.Labels,
-				Namespace:       cluster.Namespace,
-				OwnerReferences: []metav1.OwnerReference{ownerRef},
-			},
-			Spec: darkowlzzspacev1.SidecarASpec{
-				Image: cluster.Spec.Images.SidecarA,
-			},
-		}
-		if err := r.Create(ctx, sidecarA); err != nil {
-			log.Info("failed to create sidecarA", "error", err)
-		}
-
-		sidecarB := &darkowlzzspacev1.SidecarB{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:            "sidecarb-" + cluster.Name,
-				Labels:          cluster.Labels,
-				Namespace:       cluster.Namespace,
-				OwnerReferences: []metav1.OwnerReference{ownerRef},
-			},
-			Spec: darkowlzzspacev1.SidecarBSpec{
-				Image: cluster.Spec.Images.SidecarB,
-			},
-		}
-		if err := r.Create(ctx, sidecarB); err != nil {
-			log.Info("failed to create sidecarB", "error", err)
+	return r.reconcileCluster(ctx, log, &cluster, origStatus, !isPaused(&cluster))
+}
+
+// clusterStatusReconciler aggregates the status of the children of a Cluster
+// into the status of the Cluster, without updating the children. It handles
+// the status changes of the children and of their workloads, which do not
+// require the Cluster spec to be reconciled.
+type clusterStatusReconciler struct {
+	*ClusterReconciler
+}
+
+func (r *clusterStatusReconciler) Reconcile(req ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := tracer.Start(context.Background(), "Cluster.AggregateStatus", trace.WithAttributes(
+		clusterNameKey.String(req.Name), clusterNamespaceKey.String(req.Namespace)))
+	defer func() { endSpan(span, err) }()
+	log := r.Log.WithValues("cluster", req.NamespacedName)
+
+	var cluster darkowlzzspacev1.Cluster
+	fetchCtx, fetchSpan := tracer.Start(ctx, "fetch")
+	err = r.Get(fetchCtx, req.NamespacedName, &cluster)
+	endSpan(fetchSpan, client.IgnoreNotFound(err))
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// The status of a duplicate Cluster is set by the Cluster controller.
+	primary, err := r.primaryCluster(ctx, cluster.Namespace)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if primary != nil && primary.Name != cluster.Name {
+		return ctrl.Result{}, nil
+	}
+
+	return r.reconcileCluster(ctx, log, &cluster, cluster.Status.DeepCopy(), false)
+}
+
+// reconcileCluster brings the children of cluster to the desired state when
+// updateChildren is true, then aggregates the status of the children into
+// the status of cluster.
+func (r *ClusterReconciler) reconcileCluster(ctx context.Context, log logr.Logger, cluster *darkowlzzspacev1.Cluster, origStatus *darkowlzzspacev1.ClusterStatus, updateChildren bool) (ctrl.Result, error) {
+	// The Cluster is initialized once its children have been created.
+	init := updateChildren &&
+		!conditions.IsStatusConditionTrue(cluster.Status.Conditions, conditions.ConditionAvailable)
+
+	// Create or update App, SidecarA and SidecarB with cluster as the
+	// controller owner reference. When the children are not updated, their
+	// current state is only read for the status.
+	childFailed := false
+	appInstance, sidecarA, sidecarB := newChildren(cluster)
+	if updateChildren {
+		for _, child := range desiredChildren(cluster, appInstance, sidecarA, sidecarB) {
+			if err := r.reconcileChild(ctx, cluster, child); err != nil {
+				log.Info("failed to reconcile "+child.component, "error", err)
+				recordChildFailure(cluster, child.component, r.childExists(ctx, child.object))
+				childFailed = true
+			}
+		}
+	} else {
+		for _, child := range []childObject{appInstance, sidecarA, sidecarB} {
+			if err := r.fetchChild(ctx, child); err != nil {
+				return ctrl.Result{}, err
+			}
 		}
+	}
 
+	if init {
 		conditions.SetStatusCondition(&cluster.Status.Conditions, conditions.Condition{
 			Type:    conditions.ConditionAvailable,
 			Status:  corev1.ConditionTrue,
 			Reason:  "Initialized",
 			Message: "Initialized",
 		})
-		if err := r.Status().Update(ctx, &cluster); err != nil {
-			return ctrl.Result{Requeue: true}, err
+	}
+
+	// The upgrade is complete once every child has observed its latest
+	// spec. Only then are the reported versions updated.
+	ready := map[string]bool{
+		darkowlzzspacev1.AppComponent:      upToDate(appInstance, appInstance.Status.ObservedGeneration),
+		darkowlzzspacev1.SidecarAComponent: upToDate(sidecarA, sidecarA.Status.ObservedGeneration),
+		darkowlzzspacev1.SidecarBComponent: upToDate(sidecarB, sidecarB.Status.ObservedGeneration),
+	}
+	upgraded := !childFailed &&
+		ready[darkowlzzspacev1.AppComponent] &&
+		ready[darkowlzzspacev1.SidecarAComponent] &&
+		ready[darkowlzzspacev1.SidecarBComponent]
+	if upgraded {
+		cluster.Status.Versions = []darkowlzzspacev1.ComponentVersion{
+			{Name: darkowlzzspacev1.OperatorComponent, Version: version.Version},
+			{Name: darkowlzzspacev1.AppComponent, Version: appInstance.Spec.Image},
+			{Name: darkowlzzspacev1.SidecarAComponent, Version: sidecarA.Spec.Image},
+			{Name: darkowlzzspacev1.SidecarBComponent, Version: sidecarB.Spec.Image},
+		}
+	}
+	switch {
+	case isPaused(cluster):
+		setCondition(&cluster.Status.Conditions, conditions.Condition{
+			Type:    conditions.ConditionProgressing,
+			Status:  corev1.ConditionFalse,
+			Reason:  darkowlzzspacev1.ReasonPaused,
+			Message: "The reconciliation of the components is paused",
+		})
+	case upgraded:
+		setCondition(&cluster.Status.Conditions, conditions.Condition{
+			Type:    conditions.ConditionProgressing,
+			Status:  corev1.ConditionFalse,
+			Reason:  darkowlzzspacev1.ReasonUpgradeComplete,
+			Message: "All components are at the desired versions",
+		})
+	default:
+		setCondition(&cluster.Status.Conditions, conditions.Condition{
+			Type:    conditions.ConditionProgressing,
+			Status:  corev1.ConditionTrue,
+			Reason:  darkowlzzspacev1.ReasonUpgrading,
+			Message: "Waiting for components to reach the desired versions",
+		})
+	}
+	setReadyCondition(cluster)
+
+	relatedObjects, err := r.relatedObjects(ctx, cluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	cluster.Status.RelatedObjects = relatedObjects
+
+	recordClusterMetrics(cluster, ready)
+
+	// Record the generation that has been processed. The generation is only
+	// processed once all the children have been updated for it. Otherwise,
+	// as when only the status of the children is aggregated, only the
+	// conditions and related objects are updated.
+	processed := updateChildren && !childFailed
+	if processed {
+		cluster.Status.OperatorVersion = version.Version
+	}
+	if (processed && cluster.Status.ObservedGeneration != cluster.Generation) ||
+		!equality.Semantic.DeepEqual(origStatus, &cluster.Status) {
+		if processed {
+			now := metav1.Now()
+			cluster.Status.ObservedGeneration = cluster.Generation
+			cluster.Status.LastReconcileTime = &now
 		}
-		log.Info("Cluster initialised")
+		if err := r.updateStatus(ctx, cluster, origStatus); err != nil {
+			return requeueOnConflict(err)
+		}
+		if init {
+			log.Info("Cluster initialised")
+		}
+	}
+
+	return ctrl.Result{Requeue: childFailed}, nil
+}
+
+// reconcileChild applies the desired state of a child of cluster using
+// server-side apply. Only the fields set here are owned by the operator, the
+// fields set by others are left untouched. On success the child object holds
+// the state of the child returned by the API server.
+func (r *ClusterReconciler) reconcileChild(ctx context.Context, cluster *darkowlzzspacev1.Cluster, child desiredChild) (err error) {
+	ctx, span := tracer.Start(ctx, "apply", trace.WithAttributes(objectNameKey.String(child.object.GetName())))
+	defer func() { endSpan(span, err) }()
+
+	if err := setDesiredState(cluster, child, r.Scheme); err != nil {
+		return err
 	}
+	span.SetAttributes(objectKindKey.String(child.object.GetObjectKind().GroupVersionKind().Kind))
+	return r.Patch(ctx, child.object, client.Apply, client.FieldOwner(FieldManager), client.ForceOwnership)
+}
+
+// childExists returns true unless child is known not to exist.
+func (r *ClusterReconciler) childExists(ctx context.Context, child childObject) bool {
+	key := types.NamespacedName{Namespace: child.GetNamespace(), Name: child.GetName()}
+	return !apierrors.IsNotFound(r.Get(ctx, key, child.DeepCopyObject()))
+}
+
+// fetchChild reads the current state of a child. A missing child is left
+// empty.
+func (r *ClusterReconciler) fetchChild(ctx context.Context, child childObject) error {
+	key := types.NamespacedName{Namespace: child.GetNamespace(), Name: child.GetName()}
+	return client.IgnoreNotFound(r.Get(ctx, key, child))
+}
+
+// updateStatus writes the changes of the status of cluster from origStatus
+// with a status patch, see statusPatch.
+func (r *ClusterReconciler) updateStatus(ctx context.Context, cluster *darkowlzzspacev1.Cluster, origStatus *darkowlzzspacev1.ClusterStatus) (err error) {
+	ctx, span := tracer.Start(ctx, "updateStatus")
+	defer func() { endSpan(span, err) }()
+	base := cluster.DeepCopy()
+	base.Status = *origStatus
+	return r.Status().Patch(ctx, cluster, statusPatch(base), client.FieldOwner(FieldManager))
+}
 
+// statusPatch returns the patch writing the status changes of an object
+// from base. A merge patch replaces lists such as the conditions as a whole,
+// so the patch is rejected with a conflict when base is not the latest
+// version of the object, rather than dropping the concurrent changes.
+func statusPatch(base runtime.Object) client.Patch {
+	return client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{})
+}
+
+// requeueOnConflict returns the result of a reconcile that failed with err.
+// A conflict with a concurrent write is not an error, the request is
+// requeued to be reconciled against the latest version of the object.
+func requeueOnConflict(err error) (ctrl.Result, error) {
+	if apierrors.IsConflict(err) {
+		return ctrl.Result{Requeue: true}, nil
+	}
+	return ctrl.Result{}, err
+}
+
+// isPaused returns true when the reconciliation of the components of
+// cluster is paused.
+func isPaused(cluster *darkowlzzspacev1.Cluster) bool {
+	return cluster.Annotations[darkowlzzspacev1.PausedAnnotation] == "true"
+}
+
+// primaryCluster returns the Cluster that manages the given namespace, which
+// is the oldest Cluster not being deleted. Ties are broken by name.
+func (r *ClusterReconciler) primaryCluster(ctx context.Context, namespace string) (*darkowlzzspacev1.Cluster, error) {
+	var clusters darkowlzzspacev1.ClusterList
+	if err := r.List(ctx, &clusters, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	var primary *darkowlzzspacev1.Cluster
+	for i := range clusters.Items {
+		c := &clusters.Items[i]
+		if c.DeletionTimestamp != nil {
+			continue
+		}
+		if primary == nil ||
+			c.CreationTimestamp.Before(&primary.CreationTimestamp) ||
+			(c.CreationTimestamp.Equal(&primary.CreationTimestamp) && c.Name < primary.Name) {
+			primary = c
+		}
+	}
+	return primary, nil
+}
+
+// markDuplicate sets the conditions of a Cluster that is ignored because
+// the primary Cluster already manages its namespace.
+func (r *ClusterReconciler) markDuplicate(ctx context.Context, cluster *darkowlzzspacev1.Cluster, origStatus *darkowlzzspacev1.ClusterStatus, primary string) (ctrl.Result, error) {
+	message := fmt.Sprintf("Cluster %q already manages namespace %q, only one Cluster is allowed per namespace", primary, cluster.Namespace)
+	setCondition(&cluster.Status.Conditions, conditions.Condition{
+		Type:    conditions.ConditionAvailable,
+		Status:  corev1.ConditionFalse,
+		Reason:  darkowlzzspacev1.ReasonDuplicateCluster,
+		Message: message,
+	})
+	setCondition(&cluster.Status.Conditions, conditions.Condition{
+		Type:    conditions.ConditionProgressing,
+		Status:  corev1.ConditionFalse,
+		Reason:  darkowlzzspacev1.ReasonDuplicateCluster,
+		Message: message,
+	})
+	setCondition(&cluster.Status.Conditions, conditions.Condition{
+		Type:    conditions.ConditionDegraded,
+		Status:  corev1.ConditionTrue,
+		Reason:  darkowlzzspacev1.ReasonDuplicateCluster,
+		Message: message,
+	})
+	setReadyCondition(cluster)
+	recordClusterMetrics(cluster, nil)
+
+	if cluster.Status.ObservedGeneration != cluster.Generation ||
+		!equality.Semantic.DeepEqual(origStatus, &cluster.Status) {
+		now := metav1.Now()
+		cluster.Status.ObservedGeneration = cluster.Generation
+		cluster.Status.LastReconcileTime = &now
+		if err := r.updateStatus(ctx, cluster, origStatus); err != nil {
+			return requeueOnConflict(err)
+		}
+		r.Log.Info("Cluster ignored, namespace already managed", "cluster", cluster.Name, "namespace", cluster.Namespace, "primary", primary)
+	}
 	return ctrl.Result{}, nil
 }
 
-func (r *ClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&darkowlzzspacev1.Cluster{}).
-		Complete(r)
+// clustersInNamespace maps a Cluster to requests for all the other Clusters
+// in its namespace, so that a duplicate Cluster takes over once the primary
+// Cluster is deleted.
+func (r *ClusterReconciler) clustersInNamespace(obj handler.MapObject) []reconcile.Request {
+	var clusters darkowlzzspacev1.ClusterList
+	if err := r.List(context.Background(), &clusters, client.InNamespace(obj.Meta.GetNamespace())); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, c := range clusters.Items {
+		if c.Name == obj.Meta.GetName() {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: c.Namespace, Name: c.Name},
+		})
+	}
+	return requests
+}
+
+// relatedObjects returns references to all the objects managed for the
+// cluster: the child objects and the workloads created by them.
+func (r *ClusterReconciler) relatedObjects(ctx context.Context, cluster *darkowlzzspacev1.Cluster) ([]corev1.ObjectReference, error) {
+	var refs []corev1.ObjectReference
+	childUIDs := map[types.UID]bool{}
+
+	children := []runtime.Object{
+		&darkowlzzspacev1.App{},
+		&darkowlzzspacev1.SidecarA{},
+		&darkowlzzspacev1.SidecarB{},
+	}
+	names := []string{"app-" + cluster.Name, "sidecara-" + cluster.Name, "sidecarb-" + cluster.Name}
+	for i, child := range children {
+		key := types.NamespacedName{Namespace: cluster.Namespace, Name: names[i]}
+		if err := r.Get(ctx, key, child); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		ref, err := r.objectReference(child)
+		if err != nil {
+			return nil, err
+		}
+		if err := objectreferences.SetObjectReference(&refs, ref); err != nil {
+			return nil, err
+		}
+		accessor, err := meta.Accessor(child)
+		if err != nil {
+			return nil, err
+		}
+		childUIDs[accessor.GetUID()] = true
+	}
+
+	// Collect the workloads controlled by the child objects.
+	var workloadRefs []corev1.ObjectReference
+	workloads, err := r.workloads.list(cluster.Namespace, cluster.Name)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range workloads {
+		accessor, err := meta.Accessor(item)
+		if err != nil {
+			return nil, err
+		}
+		owner := metav1.GetControllerOf(accessor)
+		if owner == nil || !childUIDs[owner.UID] {
+			continue
+		}
+		ref, err := r.objectReference(item)
+		if err != nil {
+			return nil, err
+		}
+		if err := objectreferences.SetObjectReference(&workloadRefs, ref); err != nil {
+			return nil, err
+		}
+	}
+	// Cache listing order is not stable, sort to avoid needless status
+	// updates.
+	sort.Slice(workloadRefs, func(i, j int) bool {
+		if workloadRefs[i].Kind != workloadRefs[j].Kind {
+			return workloadRefs[i].Kind < workloadRefs[j].Kind
+		}
+		return workloadRefs[i].Name < workloadRefs[j].Name
+	})
+
+	return append(refs, workloadRefs...), nil
+}
+
+// objectReference returns a reference to the given object.
+func (r *ClusterReconciler) objectReference(obj runtime.Object) (corev1.ObjectReference, error) {
+	gvk, err := apiutil.GVKForObject(obj, r.Scheme)
+	if err != nil {
+		return corev1.ObjectReference{}, err
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return corev1.ObjectReference{}, err
+	}
+	apiVersion, kind := gvk.ToAPIVersionAndKind()
+	return corev1.ObjectReference{
+		APIVersion: apiVersion,
+		Kind:       kind,
+		Namespace:  accessor.GetNamespace(),
+		Name:       accessor.GetName(),
+	}, nil
+}
+
+// upToDate returns true when obj exists and its latest generation has been
+// observed by its controller.
+func upToDate(obj metav1.Object, observedGeneration int64) bool {
+	return obj.GetUID() != "" && obj.GetGeneration() == observedGeneration
+}
+
+// setCondition sets the condition only when its status, reason or message
+// differ from the existing one. Unlike conditions.SetStatusCondition, this
+// does not refresh the heartbeat time of an unchanged condition, which would
+// otherwise cause a status update on every reconcile.
+func setCondition(conds *[]conditions.Condition, condition conditions.Condition) {
+	existing := conditions.FindStatusCondition(*conds, condition.Type)
+	if existing != nil &&
+		existing.Status == condition.Status &&
+		existing.Reason == condition.Reason &&
+		existing.Message == condition.Message {
+		return
+	}
+	conditions.SetStatusCondition(conds, condition)
+}
+
+// SetupWithManager sets up the Cluster controller with options and the
+// controller aggregating the status of the Clusters with statusOptions. Both
+// controllers queue the same Cluster keys, so they must not share a rate
+// limiter.
+func (r *ClusterReconciler) SetupWithManager(mgr ctrl.Manager, options, statusOptions controller.Options) error {
+	workloads, err := newWorkloadInformers(mgr.GetConfig(), r.WatchNamespaces)
+	if err != nil {
+		return err
+	}
+	if err := mgr.Add(workloads); err != nil {
+		return err
+	}
+	r.workloads = workloads
+	workloadHandler := &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(workloadToCluster),
+	}
+	// The Cluster controller reconciles the children on the changes of the
+	// Cluster spec, labels and pause annotation, and on the changes of the
+	// children spec. Its own status writes do not trigger it.
+	childSpecChanged := builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, labelsChanged))
+	if err := ctrl.NewControllerManagedBy(mgr).
+		For(&darkowlzzspacev1.Cluster{}, builder.WithPredicates(predicate.Or(
+			predicate.GenerationChangedPredicate{},
+			labelsChanged,
+			annotationChanged(darkowlzzspacev1.PausedAnnotation),
+		))).
+		Owns(&darkowlzzspacev1.App{}, childSpecChanged).
+		Owns(&darkowlzzspacev1.SidecarA{}, childSpecChanged).
+		Owns(&darkowlzzspacev1.SidecarB{}, childSpecChanged).
+		Watches(&source.Kind{Type: &darkowlzzspacev1.Cluster{}},
+			&handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(r.clustersInNamespace)},
+			builder.WithPredicates(predicate.Funcs{
+				CreateFunc:  func(event.CreateEvent) bool { return false },
+				UpdateFunc:  func(event.UpdateEvent) bool { return false },
+				GenericFunc: func(event.GenericEvent) bool { return false },
+			})).
+		WithOptions(options).
+		Complete(r); err != nil {
+		return err
+	}
+
+	// The status changes of the children and of their workloads are only
+	// aggregated into the Cluster status.
+	childStatusChanged := builder.WithPredicates(statusChanged)
+	statusController := ctrl.NewControllerManagedBy(mgr).
+		Named("clusterstatus").
+		For(&darkowlzzspacev1.Cluster{}, builder.WithPredicates(noEvents)).
+		Owns(&darkowlzzspacev1.App{}, childStatusChanged).
+		Owns(&darkowlzzspacev1.SidecarA{}, childStatusChanged).
+		Owns(&darkowlzzspacev1.SidecarB{}, childStatusChanged)
+	for _, src := range workloads.sources() {
+		statusController = statusController.Watches(src, workloadHandler)
+	}
+	return statusController.
+		WithOptions(statusOptions).
+		Complete(&clusterStatusReconciler{ClusterReconciler: r})
 }
@@ -0,0 +1,372 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	conditions "github.com/openshift/custom-resource-status/conditions/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	darkowlzzspacev1 "github.com/darkowlzz/hco/api/v1"
+	"github.com/darkowlzz/hco/pkg/clusterutil"
+	"github.com/darkowlzz/hco/pkg/version"
+)
+
+var _ = Describe("ClusterReconciler", func() {
+	const (
+		timeout  = 30 * time.Second
+		interval = 250 * time.Millisecond
+	)
+
+	var (
+		ctx       context.Context
+		namespace string
+		images    darkowlzzspacev1.ImageReference
+	)
+
+	// children returns the children of the Cluster with the given name
+	// along with the image each of them is expected to run.
+	children := func(name string, images darkowlzzspacev1.ImageReference) map[childObject]string {
+		meta := func(prefix string) metav1.ObjectMeta {
+			return metav1.ObjectMeta{Namespace: namespace, Name: prefix + name}
+		}
+		return map[childObject]string{
+			&darkowlzzspacev1.App{ObjectMeta: meta("app-")}:           images.App,
+			&darkowlzzspacev1.SidecarA{ObjectMeta: meta("sidecara-")}: images.SidecarA,
+			&darkowlzzspacev1.SidecarB{ObjectMeta: meta("sidecarb-")}: images.SidecarB,
+		}
+	}
+
+	// childImage returns the image of a child.
+	childImage := func(child childObject) string {
+		switch child := child.(type) {
+		case *darkowlzzspacev1.App:
+			return child.Spec.Image
+		case *darkowlzzspacev1.SidecarA:
+			return child.Spec.Image
+		case *darkowlzzspacev1.SidecarB:
+			return child.Spec.Image
+		}
+		return ""
+	}
+
+	// get reads the current state of obj.
+	get := func(obj childObject) func() error {
+		return func() error {
+			key := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+			return k8sClient.Get(ctx, key, obj)
+		}
+	}
+
+	// waitForUpgrade waits for the Cluster with the given name to be
+	// upgraded.
+	waitForUpgrade := func(name string) *darkowlzzspacev1.Cluster {
+		waitCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		cluster, err := clusterutil.WaitForUpgrade(waitCtx, k8sClient, types.NamespacedName{Namespace: namespace, Name: name})
+		Expect(err).ToNot(HaveOccurred())
+		return cluster
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{GenerateName: "cluster-test-"}}
+		Expect(k8sClient.Create(ctx, ns)).To(Succeed())
+		namespace = ns.Name
+		images = clusterutil.NewImageReference().
+			WithApp("app:1").
+			WithSidecarA("sidecara:1").
+			WithSidecarB("sidecarb:1").
+			Build()
+	})
+
+	It("creates the children with the Cluster as their controller", func() {
+		cluster := clusterutil.NewCluster(namespace, "test").WithImages(images).Build()
+		Expect(k8sClient.Create(ctx, cluster)).To(Succeed())
+
+		for child, image := range children(cluster.Name, images) {
+			Eventually(get(child), timeout, interval).Should(Succeed())
+			Expect(childImage(child)).To(Equal(image))
+			owner := metav1.GetControllerOf(child)
+			Expect(owner).ToNot(BeNil())
+			Expect(owner.Kind).To(Equal("Cluster"))
+			Expect(owner.Name).To(Equal(cluster.Name))
+			Expect(owner.UID).To(Equal(cluster.UID))
+			Expect(child.GetLabels()).To(HaveKeyWithValue(darkowlzzspacev1.ClusterNameLabel, cluster.Name))
+		}
+
+		cluster = waitForUpgrade(cluster.Name)
+		Expect(conditions.IsStatusConditionTrue(cluster.Status.Conditions, darkowlzzspacev1.ConditionReady)).To(BeTrue())
+		Expect(cluster.Status.Versions).To(ContainElement(darkowlzzspacev1.ComponentVersion{
+			Name: darkowlzzspacev1.AppComponent, Version: images.App,
+		}))
+		Expect(cluster.Status.RelatedObjects).To(HaveLen(3))
+		Expect(cluster.Status.OperatorVersion).To(Equal(version.Version))
+	})
+
+	It("writes the status with the field manager of the operator", func() {
+		cluster := clusterutil.NewCluster(namespace, "test").WithImages(images).Build()
+		Expect(k8sClient.Create(ctx, cluster)).To(Succeed())
+		cluster = waitForUpgrade(cluster.Name)
+
+		// The Cluster is created by the test, its status by the operator.
+		var managers []string
+		for _, entry := range cluster.ManagedFields {
+			managers = append(managers, entry.Manager)
+		}
+		Expect(managers).To(ContainElement(FieldManager))
+		for child := range children(cluster.Name, images) {
+			Expect(get(child)()).To(Succeed())
+			Expect(child.GetManagedFields()).ToNot(BeEmpty())
+			for _, entry := range child.GetManagedFields() {
+				Expect(entry.Manager).To(Equal(FieldManager))
+			}
+		}
+	})
+
+	It("lists the labeled workloads of the children in the related objects", func() {
+		cluster := clusterutil.NewCluster(namespace, "test").WithImages(images).Build()
+		Expect(k8sClient.Create(ctx, cluster)).To(Succeed())
+		waitForUpgrade(cluster.Name)
+
+		app := &darkowlzzspacev1.App{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "app-test"}}
+		Expect(get(app)()).To(Succeed())
+		workload := func(name string, labels map[string]string) *corev1.ConfigMap {
+			configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace, Name: name, Labels: labels,
+			}}
+			Expect(controllerutil.SetControllerReference(app, configMap, scheme.Scheme)).To(Succeed())
+			return configMap
+		}
+		Expect(k8sClient.Create(ctx, workload("unlabeled", nil))).To(Succeed())
+		Expect(k8sClient.Create(ctx, workload("labeled", map[string]string{
+			darkowlzzspacev1.ClusterNameLabel: cluster.Name,
+		}))).To(Succeed())
+
+		Eventually(func() ([]corev1.ObjectReference, error) {
+			err := k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: cluster.Name}, cluster)
+			return cluster.Status.RelatedObjects, err
+		}, timeout, interval).Should(ContainElement(MatchFields(IgnoreExtras, Fields{
+			"Kind": Equal("ConfigMap"),
+			"Name": Equal("labeled"),
+		})))
+		Expect(cluster.Status.RelatedObjects).To(HaveLen(4))
+	})
+
+	It("propagates the labels of the Cluster to the children", func() {
+		cluster := clusterutil.NewCluster(namespace, "test").
+			WithImages(images).
+			WithLabels(map[string]string{"team": "a"}).
+			Build()
+		Expect(k8sClient.Create(ctx, cluster)).To(Succeed())
+
+		for child := range children(cluster.Name, images) {
+			Eventually(func() (map[string]string, error) {
+				err := get(child)()
+				return child.GetLabels(), err
+			}, timeout, interval).Should(HaveKeyWithValue("team", "a"))
+
+			// Labels set on the child by others are left untouched.
+			base := child.DeepCopyObject()
+			child.SetLabels(map[string]string{"team": "a", "other": "x"})
+			Expect(k8sClient.Patch(ctx, child, client.MergeFrom(base))).To(Succeed())
+		}
+
+		patch := client.MergeFrom(cluster.DeepCopy())
+		cluster.Labels["team"] = "b"
+		Expect(k8sClient.Patch(ctx, cluster, patch)).To(Succeed())
+
+		for child := range children(cluster.Name, images) {
+			Eventually(func() (map[string]string, error) {
+				err := get(child)()
+				return child.GetLabels(), err
+			}, timeout, interval).Should(HaveKeyWithValue("team", "b"))
+			Expect(child.GetLabels()).To(HaveKeyWithValue("other", "x"))
+		}
+	})
+
+	It("updates the images of the children", func() {
+		cluster := clusterutil.NewCluster(namespace, "test").WithImages(images).Build()
+		Expect(k8sClient.Create(ctx, cluster)).To(Succeed())
+		cluster = waitForUpgrade(cluster.Name)
+
+		updated := clusterutil.NewImageReference().
+			WithApp("app:2").
+			WithSidecarA("sidecara:2").
+			WithSidecarB("sidecarb:2").
+			Build()
+		patch := client.MergeFrom(cluster.DeepCopy())
+		cluster.Spec.Images = updated
+		Expect(k8sClient.Patch(ctx, cluster, patch)).To(Succeed())
+
+		for child, image := range children(cluster.Name, updated) {
+			Eventually(func() (string, error) {
+				err := get(child)()
+				return childImage(child), err
+			}, timeout, interval).Should(Equal(image))
+		}
+
+		cluster = waitForUpgrade(cluster.Name)
+		Expect(cluster.Status.ObservedGeneration).To(Equal(cluster.Generation))
+		Expect(cluster.Status.Versions).To(ContainElement(darkowlzzspacev1.ComponentVersion{
+			Name: darkowlzzspacev1.SidecarBComponent, Version: updated.SidecarB,
+		}))
+	})
+
+	It("forgets a deleted Cluster", func() {
+		cluster := clusterutil.NewCluster(namespace, "test").WithImages(images).Build()
+		Expect(k8sClient.Create(ctx, cluster)).To(Succeed())
+		waitForUpgrade(cluster.Name)
+
+		Expect(k8sClient.Delete(ctx, cluster)).To(Succeed())
+		Eventually(func() bool {
+			err := k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: cluster.Name}, cluster)
+			return apierrors.IsNotFound(err)
+		}, timeout, interval).Should(BeTrue())
+
+		// envtest runs no garbage collector, the children are only checked to
+		// block the deletion of their owner until they are deleted.
+		for child := range children(cluster.Name, images) {
+			Expect(get(child)()).To(Succeed())
+			owner := metav1.GetControllerOf(child)
+			Expect(owner).ToNot(BeNil())
+			Expect(owner.BlockOwnerDeletion).To(PointTo(BeTrue()))
+		}
+
+		key := types.NamespacedName{Namespace: namespace, Name: cluster.Name}
+		Eventually(func() bool {
+			clusterInfoLabels.Lock()
+			defer clusterInfoLabels.Unlock()
+			_, ok := clusterInfoLabels.labels[key]
+			return ok
+		}, timeout, interval).Should(BeFalse())
+	})
+
+	It("does not take over a child controlled by another object", func() {
+		owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "owner"}}
+		Expect(k8sClient.Create(ctx, owner)).To(Succeed())
+		app := &darkowlzzspacev1.App{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "app-test"}}
+		isController := true
+		app.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+			Name:       owner.Name,
+			UID:        owner.UID,
+			Controller: &isController,
+		}}
+		app.Spec.Image = "other:1"
+		Expect(k8sClient.Create(ctx, app)).To(Succeed())
+
+		cluster := clusterutil.NewCluster(namespace, "test").WithImages(images).Build()
+		Expect(k8sClient.Create(ctx, cluster)).To(Succeed())
+
+		failures := childUpdateFailures.With(prometheus.Labels{
+			"name": cluster.Name, "namespace": namespace, "component": darkowlzzspacev1.AppComponent,
+		})
+		Eventually(func() float64 {
+			return testutil.ToFloat64(failures)
+		}, timeout, interval).Should(BeNumerically(">", 0))
+
+		// The other children are still created.
+		for child := range children(cluster.Name, images) {
+			if _, isApp := child.(*darkowlzzspacev1.App); isApp {
+				continue
+			}
+			Eventually(get(child), timeout, interval).Should(Succeed())
+		}
+
+		Expect(get(app)()).To(Succeed())
+		Expect(metav1.GetControllerOf(app).UID).To(Equal(owner.UID))
+		Expect(app.Spec.Image).To(Equal("other:1"))
+
+		Eventually(func() (*conditions.Condition, error) {
+			err := k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: cluster.Name}, cluster)
+			return conditions.FindStatusCondition(cluster.Status.Conditions, conditions.ConditionProgressing), err
+		}, timeout, interval).Should(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status": Equal(corev1.ConditionTrue),
+			"Reason": Equal(darkowlzzspacev1.ReasonUpgrading),
+		})))
+		Expect(conditions.IsStatusConditionFalse(cluster.Status.Conditions, darkowlzzspacev1.ConditionReady)).To(BeTrue())
+
+		// The generation is not processed while a child cannot be updated.
+		Consistently(func() (*darkowlzzspacev1.ClusterStatus, error) {
+			err := k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: cluster.Name}, cluster)
+			return &cluster.Status, err
+		}, 2*time.Second, interval).Should(PointTo(MatchFields(IgnoreExtras, Fields{
+			"ObservedGeneration": BeZero(),
+			"LastReconcileTime":  BeNil(),
+			"OperatorVersion":    BeEmpty(),
+		})))
+	})
+
+	It("does not overwrite the status from a stale Cluster", func() {
+		cluster := clusterutil.NewCluster(namespace, "test").WithImages(images).Build()
+		Expect(k8sClient.Create(ctx, cluster)).To(Succeed())
+		stale := waitForUpgrade(cluster.Name)
+
+		// Another writer changes the Cluster after the stale copy was read.
+		latest := stale.DeepCopy()
+		latest.Annotations = map[string]string{"test": "changed"}
+		Expect(k8sClient.Update(ctx, latest)).To(Succeed())
+
+		r := &ClusterReconciler{Client: k8sClient, Scheme: scheme.Scheme}
+		origStatus := stale.Status.DeepCopy()
+		stale.Status.Conditions = nil
+		err := r.updateStatus(ctx, stale, origStatus)
+		Expect(apierrors.IsConflict(err)).To(BeTrue())
+		Expect(requeueOnConflict(err)).To(Equal(ctrl.Result{Requeue: true}))
+
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: cluster.Name}, latest)).To(Succeed())
+		Expect(conditions.IsStatusConditionTrue(latest.Status.Conditions, conditions.ConditionAvailable)).To(BeTrue())
+	})
+
+	It("marks a second Cluster in the namespace as degraded", func() {
+		// Clusters created within the same second are ordered by name.
+		primary := clusterutil.NewCluster(namespace, "first").WithImages(images).Build()
+		Expect(k8sClient.Create(ctx, primary)).To(Succeed())
+		waitForUpgrade(primary.Name)
+
+		duplicate := clusterutil.NewCluster(namespace, "second").WithImages(images).Build()
+		Expect(k8sClient.Create(ctx, duplicate)).To(Succeed())
+
+		waitCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		_, err := clusterutil.WaitForAvailable(waitCtx, k8sClient, types.NamespacedName{Namespace: namespace, Name: duplicate.Name})
+		var degraded *clusterutil.DegradedError
+		Expect(errors.As(err, &degraded)).To(BeTrue())
+		Expect(degraded.Reason).To(Equal(darkowlzzspacev1.ReasonDuplicateCluster))
+
+		app := &darkowlzzspacev1.App{}
+		err = k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: "app-" + duplicate.Name}, app)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+})
@@ -0,0 +1,73 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	conditions "github.com/openshift/custom-resource-status/conditions/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	darkowlzzspacev1 "github.com/darkowlzz/hco/api/v1"
+)
+
+// setReadyCondition sets the Ready condition of obj from its Available,
+// Progressing and Degraded conditions. A False Ready condition carries the
+// reason and message of the condition keeping the object from being ready,
+// checked in that order: Degraded, Available, Progressing.
+func setReadyCondition(obj darkowlzzspacev1.ConditionsAccessor) {
+	conds := obj.GetConditions()
+	ready := conditions.Condition{
+		Type:    darkowlzzspacev1.ConditionReady,
+		Status:  corev1.ConditionTrue,
+		Reason:  darkowlzzspacev1.ReasonReady,
+		Message: "Available and not progressing",
+	}
+	degraded := conditions.FindStatusCondition(conds, conditions.ConditionDegraded)
+	available := conditions.FindStatusCondition(conds, conditions.ConditionAvailable)
+	progressing := conditions.FindStatusCondition(conds, conditions.ConditionProgressing)
+	switch {
+	case degraded != nil && degraded.Status == corev1.ConditionTrue:
+		ready.Status, ready.Reason, ready.Message = corev1.ConditionFalse, degraded.Reason, degraded.Message
+	case available == nil:
+		ready.Status, ready.Reason, ready.Message = corev1.ConditionFalse, "NotAvailable", "Not available yet"
+	case available.Status != corev1.ConditionTrue:
+		ready.Status, ready.Reason, ready.Message = corev1.ConditionFalse, available.Reason, available.Message
+	case progressing != nil && progressing.Status == corev1.ConditionTrue:
+		ready.Status, ready.Reason, ready.Message = corev1.ConditionFalse, progressing.Reason, progressing.Message
+	}
+	setCondition(&conds, ready)
+	obj.SetConditions(conds)
+}
+
+// setReconciledConditions sets the conditions of a child of a Cluster that
+// has observed its latest spec.
+func setReconciledConditions(obj darkowlzzspacev1.ConditionsAccessor) {
+	conds := obj.GetConditions()
+	setCondition(&conds, conditions.Condition{
+		Type:    conditions.ConditionAvailable,
+		Status:  corev1.ConditionTrue,
+		Reason:  darkowlzzspacev1.ReasonReconciled,
+		Message: "The latest spec has been reconciled",
+	})
+	setCondition(&conds, conditions.Condition{
+		Type:    conditions.ConditionProgressing,
+		Status:  corev1.ConditionFalse,
+		Reason:  darkowlzzspacev1.ReasonReconciled,
+		Message: "The latest spec has been reconciled",
+	})
+	obj.SetConditions(conds)
+	setReadyCondition(obj)
+}
@@ -0,0 +1,186 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"sync"
+
+	conditions "github.com/openshift/custom-resource-status/conditions/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	darkowlzzspacev1 "github.com/darkowlzz/hco/api/v1"
+)
+
+// The series of a Cluster are labeled with the name and namespace of the
+// Cluster. The name label is not called cluster, which Prometheus setups
+// commonly use as an external label for the monitored Kubernetes cluster.
+var (
+	clusterCondition = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "hco_cluster_condition",
+			Help: "The status of the Cluster conditions. The series of the current status of a condition has the value 1, the others 0.",
+		},
+		[]string{"name", "namespace", "type", "status"},
+	)
+	componentReady = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "hco_cluster_component_ready",
+			Help: "Whether a component of the Cluster has observed its desired state.",
+		},
+		[]string{"name", "namespace", "component"},
+	)
+	clusterInfo = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "hco_cluster_info",
+			Help: "Information about the Cluster, including the images of its components.",
+		},
+		[]string{"name", "namespace", "app_image", "sidecara_image", "sidecarb_image"},
+	)
+	childCreateFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "hco_child_create_failures_total",
+			Help: "Total number of failures to look up or create a child object of a Cluster.",
+		},
+		[]string{"name", "namespace", "component"},
+	)
+	childUpdateFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "hco_child_update_failures_total",
+			Help: "Total number of failures to update a child object of a Cluster.",
+		},
+		[]string{"name", "namespace", "component"},
+	)
+)
+
+// clusterInfoLabels holds the last labels of the info series of every
+// Cluster, so that the stale series can be deleted when the images change.
+var clusterInfoLabels = struct {
+	sync.Mutex
+	labels map[types.NamespacedName]prometheus.Labels
+}{labels: map[types.NamespacedName]prometheus.Labels{}}
+
+var metricConditionTypes = []conditions.ConditionType{
+	conditions.ConditionAvailable,
+	conditions.ConditionProgressing,
+	conditions.ConditionDegraded,
+	conditions.ConditionUpgradeable,
+	darkowlzzspacev1.ConditionReady,
+}
+
+var metricConditionStatuses = []corev1.ConditionStatus{
+	corev1.ConditionTrue,
+	corev1.ConditionFalse,
+	corev1.ConditionUnknown,
+}
+
+var metricComponents = []string{
+	darkowlzzspacev1.AppComponent,
+	darkowlzzspacev1.SidecarAComponent,
+	darkowlzzspacev1.SidecarBComponent,
+}
+
+func init() {
+	metrics.Registry.MustRegister(
+		clusterCondition,
+		componentReady,
+		clusterInfo,
+		childCreateFailures,
+		childUpdateFailures,
+	)
+}
+
+// recordClusterMetrics updates the metrics of the cluster. ready holds the
+// readiness of every component, keyed by component name.
+func recordClusterMetrics(cluster *darkowlzzspacev1.Cluster, ready map[string]bool) {
+	for _, conditionType := range metricConditionTypes {
+		condition := conditions.FindStatusCondition(cluster.Status.Conditions, conditionType)
+		for _, status := range metricConditionStatuses {
+			labels := prometheus.Labels{
+				"name":      cluster.Name,
+				"namespace": cluster.Namespace,
+				"type":      string(conditionType),
+				"status":    string(status),
+			}
+			if condition == nil {
+				clusterCondition.Delete(labels)
+				continue
+			}
+			clusterCondition.With(labels).Set(boolToFloat(condition.Status == status))
+		}
+	}
+
+	for component, isReady := range ready {
+		componentReady.WithLabelValues(cluster.Name, cluster.Namespace, component).Set(boolToFloat(isReady))
+	}
+
+	key := types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}
+	labels := prometheus.Labels{
+		"name":           cluster.Name,
+		"namespace":      cluster.Namespace,
+		"app_image":      cluster.Spec.Images.App,
+		"sidecara_image": cluster.Spec.Images.SidecarA,
+		"sidecarb_image": cluster.Spec.Images.SidecarB,
+	}
+	clusterInfoLabels.Lock()
+	defer clusterInfoLabels.Unlock()
+	if old, ok := clusterInfoLabels.labels[key]; ok {
+		clusterInfo.Delete(old)
+	}
+	clusterInfo.With(labels).Set(1)
+	clusterInfoLabels.labels[key] = labels
+}
+
+// deleteClusterMetrics deletes the series of a deleted cluster.
+func deleteClusterMetrics(key types.NamespacedName) {
+	for _, conditionType := range metricConditionTypes {
+		for _, status := range metricConditionStatuses {
+			clusterCondition.DeleteLabelValues(key.Name, key.Namespace, string(conditionType), string(status))
+		}
+	}
+	for _, component := range metricComponents {
+		componentReady.DeleteLabelValues(key.Name, key.Namespace, component)
+		childCreateFailures.DeleteLabelValues(key.Name, key.Namespace, component)
+		childUpdateFailures.DeleteLabelValues(key.Name, key.Namespace, component)
+	}
+
+	clusterInfoLabels.Lock()
+	defer clusterInfoLabels.Unlock()
+	if old, ok := clusterInfoLabels.labels[key]; ok {
+		clusterInfo.Delete(old)
+		delete(clusterInfoLabels.labels, key)
+	}
+}
+
+// recordChildFailure counts a failure to create or update a child object of
+// the cluster. A failure is a create failure when the child does not exist.
+func recordChildFailure(cluster *darkowlzzspacev1.Cluster, component string, exists bool) {
+	if !exists {
+		childCreateFailures.WithLabelValues(cluster.Name, cluster.Namespace, component).Inc()
+		return
+	}
+	childUpdateFailures.WithLabelValues(cluster.Name, cluster.Namespace, component).Inc()
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
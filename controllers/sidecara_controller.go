@@ -17,12 +17,15 @@ limitations under the License.
 package controllers
 
 import (
-	"context"
-
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	darkowlzzspacev1 "github.com/darkowlzz/hco/api/v1"
 )
@@ -37,17 +40,45 @@ type SidecarAReconciler struct {
 // +kubebuilder:rbac:groups=darkowlzz.space,resources=sidecaras,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=darkowlzz.space,resources=sidecaras/status,verbs=get;update;patch
 
-func (r *SidecarAReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
-	_ = context.Background()
-	_ = r.Log.WithValues("sidecara", req.NamespacedName)
+func (r *SidecarAReconciler) Reconcile(req ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := startReconcileSpan("SidecarA", req)
+	defer func() { endSpan(span, err) }()
+	log := r.Log.WithValues("sidecara", req.NamespacedName)
+
+	var sidecarA darkowlzzspacev1.SidecarA
+	fetchCtx, fetchSpan := tracer.Start(ctx, "fetch")
+	err = r.Get(fetchCtx, req.NamespacedName, &sidecarA)
+	endSpan(fetchSpan, client.IgnoreNotFound(err))
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	setClusterAttributes(span, &sidecarA)
 
 	// your logic here
 
+	// Record the generation that has been processed.
+	base := sidecarA.DeepCopy()
+	setReconciledConditions(&sidecarA)
+	if sidecarA.Status.ObservedGeneration != sidecarA.Generation ||
+		!equality.Semantic.DeepEqual(base.Status, sidecarA.Status) {
+		now := metav1.Now()
+		sidecarA.Status.ObservedGeneration = sidecarA.Generation
+		sidecarA.Status.LastReconcileTime = &now
+		statusCtx, statusSpan := tracer.Start(ctx, "updateStatus")
+		err = r.Status().Patch(statusCtx, &sidecarA, statusPatch(base), client.FieldOwner(FieldManager))
+		endSpan(statusSpan, err)
+		if err != nil {
+			return requeueOnConflict(err)
+		}
+		log.Info("SidecarA reconciled", "generation", sidecarA.Generation)
+	}
+
 	return ctrl.Result{}, nil
 }
 
-func (r *SidecarAReconciler) SetupWithManager(mgr ctrl.Manager) error {
+func (r *SidecarAReconciler) SetupWithManager(mgr ctrl.Manager, options controller.Options) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&darkowlzzspacev1.SidecarA{}).
+		For(&darkowlzzspacev1.SidecarA{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithOptions(options).
 		Complete(r)
 }
@@ -17,12 +17,15 @@ limitations under the License.
 package controllers
 
 import (
-	"context"
-
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	darkowlzzspacev1 "github.com/darkowlzz/hco/api/v1"
 )
@@ -37,17 +40,45 @@ type SidecarBReconciler struct {
 // +kubebuilder:rbac:groups=darkowlzz.space,resources=sidecarbs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=darkowlzz.space,resources=sidecarbs/status,verbs=get;update;patch
 
-func (r *SidecarBReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
-	_ = context.Background()
-	_ = r.Log.WithValues("sidecarb", req.NamespacedName)
+func (r *SidecarBReconciler) Reconcile(req ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := startReconcileSpan("SidecarB", req)
+	defer func() { endSpan(span, err) }()
+	log := r.Log.WithValues("sidecarb", req.NamespacedName)
+
+	var sidecarB darkowlzzspacev1.SidecarB
+	fetchCtx, fetchSpan := tracer.Start(ctx, "fetch")
+	err = r.Get(fetchCtx, req.NamespacedName, &sidecarB)
+	endSpan(fetchSpan, client.IgnoreNotFound(err))
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	setClusterAttributes(span, &sidecarB)
 
 	// your logic here
 
+	// Record the generation that has been processed.
+	base := sidecarB.DeepCopy()
+	setReconciledConditions(&sidecarB)
+	if sidecarB.Status.ObservedGeneration != sidecarB.Generation ||
+		!equality.Semantic.DeepEqual(base.Status, sidecarB.Status) {
+		now := metav1.Now()
+		sidecarB.Status.ObservedGeneration = sidecarB.Generation
+		sidecarB.Status.LastReconcileTime = &now
+		statusCtx, statusSpan := tracer.Start(ctx, "updateStatus")
+		err = r.Status().Patch(statusCtx, &sidecarB, statusPatch(base), client.FieldOwner(FieldManager))
+		endSpan(statusSpan, err)
+		if err != nil {
+			return requeueOnConflict(err)
+		}
+		log.Info("SidecarB reconciled", "generation", sidecarB.Generation)
+	}
+
 	return ctrl.Result{}, nil
 }
 
-func (r *SidecarBReconciler) SetupWithManager(mgr ctrl.Manager) error {
+func (r *SidecarBReconciler) SetupWithManager(mgr ctrl.Manager, options controller.Options) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&darkowlzzspacev1.SidecarB{}).
+		For(&darkowlzzspacev1.SidecarB{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithOptions(options).
 		Complete(r)
 }
@@ -0,0 +1,76 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	darkowlzzspacev1 "github.com/darkowlzz/hco/api/v1"
+)
+
+// tracer creates the spans of the reconciles. It is a no-op until a tracer
+// provider is installed.
+var tracer = otel.Tracer("github.com/darkowlzz/hco/controllers")
+
+// Attributes of the reconcile spans.
+const (
+	clusterNameKey      = attribute.Key("hco.cluster.name")
+	clusterNamespaceKey = attribute.Key("hco.cluster.namespace")
+	objectKindKey       = attribute.Key("hco.object.kind")
+	objectNameKey       = attribute.Key("hco.object.name")
+)
+
+// startReconcileSpan starts the span of a reconcile of an object of the
+// given kind. The reconcile of a Cluster carries the Cluster attributes
+// right away, the reconcile of a child gets them from setClusterAttributes
+// once the child has been fetched.
+func startReconcileSpan(kind string, req ctrl.Request) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{
+		objectKindKey.String(kind),
+		objectNameKey.String(req.Name),
+	}
+	if kind == "Cluster" {
+		attrs = append(attrs, clusterNameKey.String(req.Name), clusterNamespaceKey.String(req.Namespace))
+	}
+	return tracer.Start(context.Background(), kind+".Reconcile", trace.WithAttributes(attrs...))
+}
+
+// setClusterAttributes sets the Cluster attributes of the span of a child
+// reconcile from the controller of the child.
+func setClusterAttributes(span trace.Span, child metav1.Object) {
+	owner := metav1.GetControllerOf(child)
+	if owner == nil || owner.Kind != "Cluster" || owner.APIVersion != darkowlzzspacev1.GroupVersion.String() {
+		return
+	}
+	span.SetAttributes(clusterNameKey.String(owner.Name), clusterNamespaceKey.String(child.GetNamespace()))
+}
+
+// endSpan records err, if any, on span and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,175 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	darkowlzzspacev1 "github.com/darkowlzz/hco/api/v1"
+)
+
+// workloadInformers watch the workloads of the children of the Clusters.
+// Only the objects labeled with the name of a Cluster are listed and
+// watched, so that the memory used does not grow with the other
+// Deployments, Services and ConfigMaps of the watched namespaces.
+type workloadInformers struct {
+	// factories are the informer factories by namespace. The factory of the
+	// empty namespace watches all namespaces.
+	factories map[string]informers.SharedInformerFactory
+}
+
+// newWorkloadInformers returns the workload informers of the given
+// namespaces, all namespaces when empty.
+func newWorkloadInformers(config *rest.Config, namespaces []string) (*workloadInformers, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+	w := &workloadInformers{factories: map[string]informers.SharedInformerFactory{}}
+	for _, namespace := range namespaces {
+		factory := informers.NewSharedInformerFactoryWithOptions(clientset, 0,
+			informers.WithNamespace(namespace),
+			informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+				opts.LabelSelector = darkowlzzspacev1.ClusterNameLabel
+			}))
+		// Register the informers before the factory is started.
+		factory.Apps().V1().Deployments().Informer()
+		factory.Core().V1().Services().Informer()
+		factory.Core().V1().ConfigMaps().Informer()
+		w.factories[namespace] = factory
+	}
+	return w, nil
+}
+
+// Start implements manager.Runnable.
+func (w *workloadInformers) Start(stop <-chan struct{}) error {
+	for _, factory := range w.factories {
+		factory.Start(stop)
+	}
+	<-stop
+	return nil
+}
+
+var _ manager.Runnable = &workloadInformers{}
+
+// factory returns the informer factory watching namespace.
+func (w *workloadInformers) factory(namespace string) (informers.SharedInformerFactory, error) {
+	if factory, ok := w.factories[namespace]; ok {
+		return factory, nil
+	}
+	if factory, ok := w.factories[metav1.NamespaceAll]; ok {
+		return factory, nil
+	}
+	return nil, fmt.Errorf("namespace %q is not watched", namespace)
+}
+
+// list returns the workloads of the Cluster with the given name.
+func (w *workloadInformers) list(namespace, cluster string) ([]runtime.Object, error) {
+	factory, err := w.factory(namespace)
+	if err != nil {
+		return nil, err
+	}
+	selector := labels.SelectorFromSet(labels.Set{darkowlzzspacev1.ClusterNameLabel: cluster})
+	var objs []runtime.Object
+	deployments, err := factory.Apps().V1().Deployments().Lister().Deployments(namespace).List(selector)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range deployments {
+		objs = append(objs, obj)
+	}
+	services, err := factory.Core().V1().Services().Lister().Services(namespace).List(selector)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range services {
+		objs = append(objs, obj)
+	}
+	configMaps, err := factory.Core().V1().ConfigMaps().Lister().ConfigMaps(namespace).List(selector)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range configMaps {
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+// sources returns the sources of the events of the workloads.
+func (w *workloadInformers) sources() []source.Source {
+	var sources []source.Source
+	for _, factory := range w.factories {
+		sources = append(sources,
+			&informerSource{informer: factory.Apps().V1().Deployments().Informer()},
+			&informerSource{informer: factory.Core().V1().Services().Informer()},
+			&informerSource{informer: factory.Core().V1().ConfigMaps().Informer()},
+		)
+	}
+	return sources
+}
+
+// informerSource is the source of the events of an informer. Unlike
+// source.Informer, the controller waits for the informer to sync before
+// starting its workers.
+type informerSource struct {
+	informer toolscache.SharedIndexInformer
+}
+
+var _ source.SyncingSource = &informerSource{}
+
+// Start implements source.Source.
+func (s *informerSource) Start(h handler.EventHandler, queue workqueue.RateLimitingInterface, prct ...predicate.Predicate) error {
+	return (&source.Informer{Informer: s.informer}).Start(h, queue, prct...)
+}
+
+// WaitForSync implements source.SyncingSource.
+func (s *informerSource) WaitForSync(stop <-chan struct{}) error {
+	if !toolscache.WaitForCacheSync(stop, s.informer.HasSynced) {
+		return fmt.Errorf("failed to wait for the workload informer to sync")
+	}
+	return nil
+}
+
+// workloadToCluster maps a workload to a request for the Cluster named by
+// its label.
+func workloadToCluster(obj handler.MapObject) []reconcile.Request {
+	name := obj.Meta.GetLabels()[darkowlzzspacev1.ClusterNameLabel]
+	if name == "" {
+		return nil
+	}
+	return []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: obj.Meta.GetNamespace(), Name: name}},
+	}
+}
@@ -0,0 +1,149 @@
+#!/usr/bin/env bash
+
+# Builds the manifests of the operator watching a comma-separated list of
+# namespaces, with the manager permissions granted by a Role and RoleBinding
+# in each of them instead of a ClusterRole. The operator is deployed in the
+# namespace set in config/namespaced/kustomization.yaml, which keeps a Role
+# for the PrometheusRule of the alerts when it is not listed. Without a list,
+# the operator watches the namespace it is deployed in.
+#
+# Usage: hack/build-namespaced.sh [NAMESPACE[,NAMESPACE...]]
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+ROOT=$(cd "$(dirname "${BASH_SOURCE[0]}")/.." && pwd)
+KUSTOMIZE=${KUSTOMIZE:-kustomize}
+NAMESPACES=${1:-}
+
+if [ -z "${NAMESPACES}" ]; then
+	exec "${KUSTOMIZE}" build "${ROOT}/config/namespaced"
+fi
+
+OPERATOR_NAMESPACE=$(sed -n 's/^namespace: //p' "${ROOT}/config/namespaced/kustomization.yaml")
+# Kustomize only loads bases from relative paths, the overlay is built in
+# the config directory.
+OVERLAY=$(mktemp -d "${ROOT}/config/.namespaced.XXXXXX")
+trap 'rm -rf "${OVERLAY}"' EXIT
+
+# The overlay adds a Role to each watched namespace. The operator namespace
+# keeps the Role built by config/namespaced: with all the manager
+# permissions when it is watched, and otherwise with the permissions on the
+# PrometheusRule of the alerts only (see pkg/alerts), which is maintained in
+# the operator namespace.
+OPERATOR_WATCHED=false
+for namespace in ${NAMESPACES//,/ }; do
+	if [ "${namespace}" = "${OPERATOR_NAMESPACE}" ]; then
+		OPERATOR_WATCHED=true
+	fi
+done
+
+mkdir "${OVERLAY}/operator"
+cat > "${OVERLAY}/operator/kustomization.yaml" <<END
+bases:
+- ../../namespaced
+
+patchesStrategicMerge:
+- watch_namespace_patch.yaml
+END
+cat > "${OVERLAY}/operator/watch_namespace_patch.yaml" <<END
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: hco-controller-manager
+  namespace: ${OPERATOR_NAMESPACE}
+spec:
+  template:
+    spec:
+      containers:
+      - name: manager
+        env:
+        - name: WATCH_NAMESPACE
+          value: "${NAMESPACES}"
+          valueFrom: null
+END
+if [ "${OPERATOR_WATCHED}" = false ]; then
+	cat >> "${OVERLAY}/operator/kustomization.yaml" <<END
+
+patchesJson6902:
+- target:
+    group: rbac.authorization.k8s.io
+    version: v1
+    kind: Role
+    name: hco-manager-role
+    namespace: ${OPERATOR_NAMESPACE}
+  path: alerts_role_patch.yaml
+END
+	cat > "${OVERLAY}/operator/alerts_role_patch.yaml" <<END
+- op: replace
+  path: /rules
+  value:
+  - apiGroups:
+    - monitoring.coreos.com
+    resources:
+    - prometheusrules
+    verbs:
+    - create
+    - delete
+    - get
+    - patch
+END
+fi
+
+for namespace in ${NAMESPACES//,/ }; do
+	if [ "${namespace}" = "${OPERATOR_NAMESPACE}" ]; then
+		continue
+	fi
+	mkdir "${OVERLAY}/${namespace}"
+	cp "${ROOT}/config/rbac/role.yaml" "${ROOT}/config/rbac/role_binding.yaml" \
+		"${ROOT}/config/namespaced/rbac/role_patch.yaml" "${ROOT}/config/namespaced/rbac/role_binding_patch.yaml" \
+		"${OVERLAY}/${namespace}"
+	cat > "${OVERLAY}/${namespace}/kustomization.yaml" <<END
+namePrefix: hco-
+
+resources:
+- role.yaml
+- role_binding.yaml
+
+patchesJson6902:
+- target:
+    group: rbac.authorization.k8s.io
+    version: v1
+    kind: ClusterRole
+    name: manager-role
+  path: role_patch.yaml
+- target:
+    group: rbac.authorization.k8s.io
+    version: v1
+    kind: ClusterRoleBinding
+    name: manager-rolebinding
+  path: role_binding_patch.yaml
+END
+	# The namespace transformer skips the cluster-scoped kinds of role.yaml
+	# and role_binding.yaml, the patches set it.
+	for patch in role_patch.yaml role_binding_patch.yaml; do
+		cat >> "${OVERLAY}/${namespace}/${patch}" <<END
+- op: add
+  path: /metadata/namespace
+  value: ${namespace}
+END
+	done
+	# The manager service account is in the operator namespace.
+	cat >> "${OVERLAY}/${namespace}/role_binding_patch.yaml" <<END
+- op: replace
+  path: /subjects/0/namespace
+  value: ${OPERATOR_NAMESPACE}
+END
+done
+
+# Kustomize identifies the resources of a base without their namespace, the
+# Roles of the watched namespaces are built one by one.
+"${KUSTOMIZE}" build "${OVERLAY}/operator"
+for namespace in ${NAMESPACES//,/ }; do
+	if [ "${namespace}" = "${OPERATOR_NAMESPACE}" ]; then
+		continue
+	fi
+	echo "---"
+	"${KUSTOMIZE}" build "${OVERLAY}/${namespace}"
+done
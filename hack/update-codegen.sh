@@ -0,0 +1,57 @@
+#!/usr/bin/env bash
+
+# Generates the typed clientset, listers and informers of the
+# darkowlzz.space/v1 API under pkg/client.
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+MODULE=github.com/darkowlzz/hco
+APIS=${MODULE}/api/v1
+OUTPUT_PKG=${MODULE}/pkg/client
+ROOT=$(cd "$(dirname "${BASH_SOURCE[0]}")/.." && pwd)
+BOILERPLATE=${ROOT}/hack/boilerplate.go.txt
+BIN=${CODEGEN_BIN:-$(go env GOPATH)/bin}
+
+# The generators write to a GOPATH-like tree, which is copied back into the
+# module afterwards.
+OUTPUT_BASE=$(mktemp -d)
+
+cd "${ROOT}"
+
+# The generators take the group from the directory name of the API package
+# and read a directory named "api" as the core group. They are pointed at a
+# link named after the group instead, and the import path is fixed
+# afterwards.
+LINK_DIR=hack/.codegen
+mkdir -p "${LINK_DIR}/darkowlzz"
+ln -sfn ../../../api/v1 "${LINK_DIR}/darkowlzz/v1"
+trap 'rm -rf "${OUTPUT_BASE}" "${ROOT}/${LINK_DIR}"' EXIT
+
+"${BIN}/client-gen" \
+  --clientset-name versioned \
+  --input-base "${MODULE}/${LINK_DIR}" \
+  --input darkowlzz/v1 \
+  --output-package "${OUTPUT_PKG}/clientset" \
+  --output-base "${OUTPUT_BASE}" \
+  --go-header-file "${BOILERPLATE}"
+
+"${BIN}/lister-gen" \
+  --input-dirs "${MODULE}/${LINK_DIR}/darkowlzz/v1" \
+  --output-package "${OUTPUT_PKG}/listers" \
+  --output-base "${OUTPUT_BASE}" \
+  --go-header-file "${BOILERPLATE}"
+
+"${BIN}/informer-gen" \
+  --input-dirs "${MODULE}/${LINK_DIR}/darkowlzz/v1" \
+  --versioned-clientset-package "${OUTPUT_PKG}/clientset/versioned" \
+  --listers-package "${OUTPUT_PKG}/listers" \
+  --output-package "${OUTPUT_PKG}/informers" \
+  --output-base "${OUTPUT_BASE}" \
+  --go-header-file "${BOILERPLATE}"
+
+rm -rf "${ROOT}/pkg/client"
+cp -r "${OUTPUT_BASE}/${OUTPUT_PKG}" "${ROOT}/pkg/client"
+grep -rl "${MODULE}/${LINK_DIR}/darkowlzz/v1" "${ROOT}/pkg/client" |
+  xargs sed -i "s|${MODULE}/${LINK_DIR}/darkowlzz/v1|${APIS}|g"
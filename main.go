@@ -18,17 +18,27 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"os"
+	"sort"
+	"strings"
 
+	uberzap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	darkowlzzspacev1 "github.com/darkowlzz/hco/api/v1"
-	"github.com/darkowlzz/hco/controllers"
+	"github.com/darkowlzz/hco/pkg/config"
+	"github.com/darkowlzz/hco/pkg/health"
+	"github.com/darkowlzz/hco/pkg/version"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -44,66 +54,177 @@ func init() {
 	// +kubebuilder:scaffold:scheme
 }
 
+// command is a subcommand of the manager binary.
+type command struct {
+	// summary is the one line description of the command.
+	summary string
+
+	// run runs the command with its arguments and returns the exit code.
+	run func(args []string) int
+}
+
+// commands are the subcommands of the manager binary, by name.
+var commands = map[string]command{
+	"run":         {summary: "Run the controller manager.", run: runManager},
+	"webhook":     {summary: "Run the admission webhook server only.", run: runWebhook},
+	"render":      {summary: "Print the resources the operator produces for Clusters.", run: runRender},
+	"validate":    {summary: "Validate Clusters offline.", run: runValidate},
+	"must-gather": {summary: "Collect the diagnostics of Clusters into a tarball.", run: runMustGather},
+	"version":     {summary: "Print the version of the operator.", run: runVersion},
+}
+
+// defaultCommand runs when the binary is invoked without a command, which
+// keeps the deployments that only pass flags working.
+const defaultCommand = "run"
+
 func main() {
-	var metricsAddr string
-	var enableLeaderElection bool
-	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
-	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
-		"Enable leader election for controller manager. "+
-			"Enabling this will ensure there is only one active controller manager.")
-	flag.Parse()
-
-	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
-
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-		Scheme:             scheme,
-		MetricsBindAddress: metricsAddr,
-		Port:               9443,
-		LeaderElection:     enableLeaderElection,
-		LeaderElectionID:   "25a4e4dd.",
-	})
-	if err != nil {
-		setupLog.Error(err, "unable to start manager")
-		os.Exit(1)
-	}
-
-	if err = (&controllers.ClusterReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Cluster"),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "Cluster")
-		os.Exit(1)
-	}
-	if err = (&controllers.AppReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("App"),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "App")
-		os.Exit(1)
-	}
-	if err = (&controllers.SidecarAReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("SidecarA"),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "SidecarA")
-		os.Exit(1)
-	}
-	if err = (&controllers.SidecarBReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("SidecarB"),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "SidecarB")
-		os.Exit(1)
-	}
-	// +kubebuilder:scaffold:builder
-
-	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
-		setupLog.Error(err, "problem running manager")
-		os.Exit(1)
+	name, args := defaultCommand, os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	if name == "help" {
+		usage(os.Stdout)
+		return
+	}
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+	os.Exit(cmd.run(args))
+}
+
+// usage prints the commands of the binary.
+func usage(w *os.File) {
+	fmt.Fprintf(w, "Usage: %s COMMAND [FLAGS]\n\nCommands:\n", os.Args[0])
+	var names []string
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-12s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintf(w, "\nRun '%s COMMAND -h' for the flags of a command. The default command is %s.\n", os.Args[0], defaultCommand)
+}
+
+// newFlagSet returns the flag set of a command, with a usage showing the
+// synopsis of the command.
+func newFlagSet(name, synopsis string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s %s %s\n\nFlags:\n", os.Args[0], name, synopsis)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseServerFlags parses the flags of a server command, the configuration
+// flags of cfg bound to fs by the caller, --config and the logging flags. It
+// loads the configuration file, validates the configuration and sets up the
+// logger. The returned level controls the level of the logger.
+func parseServerFlags(fs *flag.FlagSet, cfg *config.ManagerConfiguration, args []string) (uberzap.AtomicLevel, error) {
+	var configFile string
+	fs.StringVar(&configFile, "config", "",
+		"The manager configuration file. Command line flags override the values set in the file.")
+	logOptions := zap.Options{}
+	logOptions.BindFlags(fs)
+	_ = fs.Parse(args)
+
+	if configFile != "" {
+		// Flags set on the command line take precedence over the file, so
+		// re-apply them once the file is loaded.
+		setFlags := map[string]string{}
+		fs.Visit(func(f *flag.Flag) { setFlags[f.Name] = f.Value.String() })
+		if err := cfg.Load(configFile); err != nil {
+			return uberzap.AtomicLevel{}, err
+		}
+		for name, value := range setFlags {
+			if err := fs.Set(name, value); err != nil {
+				return uberzap.AtomicLevel{}, err
+			}
+		}
+	}
+	if err := cfg.Validate().ToAggregate(); err != nil {
+		return uberzap.AtomicLevel{}, fmt.Errorf("invalid manager configuration: %w", err)
+	}
+
+	// Keep a handle on the log level so that it can be changed at runtime
+	// through the log level endpoint.
+	logLevel, ok := logOptions.Level.(uberzap.AtomicLevel)
+	if !ok {
+		logLevel = uberzap.NewAtomicLevelAt(zapcore.InfoLevel)
+		if logOptions.Development {
+			logLevel.SetLevel(zapcore.DebugLevel)
+		}
+		logOptions.Level = logLevel
+	}
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&logOptions)))
+
+	info := version.Get()
+	setupLog.Info("build information", "version", info.Version, "commit", info.Commit,
+		"buildDate", info.BuildDate, "goVersion", info.GoVersion, "platform", info.Platform)
+	return logLevel, nil
+}
+
+// managerOptions returns the options of the manager of a server command.
+func managerOptions(cfg *config.ManagerConfiguration) ctrl.Options {
+	options := ctrl.Options{
+		Scheme:                  scheme,
+		MetricsBindAddress:      cfg.Metrics.BindAddress,
+		HealthProbeBindAddress:  cfg.Health.HealthProbeBindAddress,
+		Port:                    cfg.Webhook.Port,
+		CertDir:                 cfg.Webhook.CertDir,
+		LeaderElection:          cfg.LeaderElection.LeaderElect,
+		LeaderElectionID:        cfg.LeaderElection.ResourceName,
+		LeaderElectionNamespace: cfg.LeaderElection.ResourceNamespace,
+		LeaseDuration:           &cfg.LeaderElection.LeaseDuration.Duration,
+		RenewDeadline:           &cfg.LeaderElection.RenewDeadline.Duration,
+		RetryPeriod:             &cfg.LeaderElection.RetryPeriod.Duration,
+		SyncPeriod:              &cfg.SyncPeriod.Duration,
+	}
+
+	// Restrict the manager cache to the watched namespaces.
+	namespaces := cfg.WatchNamespaces
+	switch len(namespaces) {
+	case 0:
+		setupLog.Info("watching all namespaces")
+	case 1:
+		setupLog.Info("watching a single namespace", "namespace", namespaces[0])
+		options.Namespace = namespaces[0]
+	default:
+		setupLog.Info("watching multiple namespaces", "namespaces", namespaces)
+		options.NewCache = cache.MultiNamespacedCacheBuilder(namespaces)
+	}
+	return options
+}
+
+// addServerEndpoints adds the endpoints shared by the server commands to mgr:
+// the log level and version endpoints, the health check and the readiness
+// checks.
+func addServerEndpoints(mgr manager.Manager, cfg *config.ManagerConfiguration, logLevel uberzap.AtomicLevel) error {
+	// The log level endpoint is served along with the metrics. A GET returns
+	// the current level, a PUT with a body like {"level":"debug"} changes it.
+	if err := mgr.AddMetricsExtraHandler("/debug/loglevel", logLevel); err != nil {
+		return fmt.Errorf("unable to serve the log level endpoint: %w", err)
+	}
+	// The version endpoint is also behind the auth proxy, it is readable with
+	// the version-reader ClusterRole.
+	if err := mgr.AddMetricsExtraHandler("/version", version.Handler()); err != nil {
+		return fmt.Errorf("unable to serve the version endpoint: %w", err)
+	}
+
+	if err := mgr.AddHealthzCheck("ping", healthz.Ping); err != nil {
+		return fmt.Errorf("unable to set up health check: %w", err)
+	}
+	if err := mgr.AddReadyzCheck("cache-sync", health.CacheSyncCheck(mgr.GetCache())); err != nil {
+		return fmt.Errorf("unable to set up ready check cache-sync: %w", err)
+	}
+	if cfg.Webhook.Enabled {
+		if err := mgr.AddReadyzCheck("webhook-certs", health.WebhookCertCheck(cfg.Webhook.CertDir)); err != nil {
+			return fmt.Errorf("unable to set up ready check webhook-certs: %w", err)
+		}
 	}
+	return nil
 }
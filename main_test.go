@@ -0,0 +1,74 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/darkowlzz/hco/pkg/config"
+)
+
+// TestParseServerFlagsOverrideFile checks that the flags set on the command
+// line take precedence over the configuration file, and that the values set
+// only in the file are kept.
+func TestParseServerFlagsOverrideFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hco-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "config.yaml")
+	data := []byte(`apiVersion: config.darkowlzz.space/v1alpha1
+kind: ManagerConfiguration
+metrics:
+  bindAddress: 127.0.0.1:9090
+watchNamespaces:
+- from-file-a
+- from-file-b
+syncPeriod: 1h
+`)
+	if err := ioutil.WriteFile(file, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fs := newFlagSet("run", "[FLAGS]")
+	cfg := config.Default()
+	cfg.BindFlags(fs)
+	_, err = parseServerFlags(fs, cfg, []string{
+		"--config", file,
+		"--watch-namespace", "from-flag-a,from-flag-b",
+		"--sync-period", "2h",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"from-flag-a", "from-flag-b"}; !reflect.DeepEqual(cfg.WatchNamespaces, want) {
+		t.Errorf("expected the watched namespaces %v, got %v", want, cfg.WatchNamespaces)
+	}
+	if want := 2 * time.Hour; cfg.SyncPeriod.Duration != want {
+		t.Errorf("expected the sync period %s, got %s", want, cfg.SyncPeriod.Duration)
+	}
+	if want := "127.0.0.1:9090"; cfg.Metrics.BindAddress != want {
+		t.Errorf("expected the metrics address %s from the file, got %s", want, cfg.Metrics.BindAddress)
+	}
+}
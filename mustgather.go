@@ -0,0 +1,102 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/darkowlzz/hco/pkg/mustgather"
+)
+
+// runMustGather collects the diagnostics of the Clusters of a namespace into
+// a timestamped tarball. It returns the exit code.
+func runMustGather(args []string) int {
+	fs := newFlagSet("must-gather", "--namespace NAMESPACE [FLAGS]")
+	var opts mustgather.Options
+	var selector, destDir string
+	fs.StringVar(&opts.Namespace, "namespace", "", "The namespace of the Clusters.")
+	fs.StringVar(&opts.ClusterName, "cluster", "", "The name of the Cluster to collect. All the Clusters of the namespace are collected when empty.")
+	fs.StringVar(&opts.OperatorNamespace, "operator-namespace", "hco-system", "The namespace the operator runs in.")
+	fs.StringVar(&selector, "operator-selector", "control-plane=controller-manager", "The label selector of the operator pods.")
+	fs.Int64Var(&opts.LogTailLines, "log-tail-lines", 0, "The number of lines collected from the log of each container, 0 for the whole log.")
+	fs.StringVar(&destDir, "dest-dir", ".", "The directory the tarball is written to.")
+	_ = fs.Parse(args)
+	if opts.Namespace == "" || fs.NArg() > 0 {
+		fs.Usage()
+		return 2
+	}
+	var err error
+	if opts.OperatorSelector, err = labels.Parse(selector); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid operator selector: %v\n", err)
+		return 2
+	}
+
+	ctrl.SetLogger(zap.New())
+	file, err := mustGather(context.Background(), opts, destDir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Printf("Wrote %s\n", file)
+	return 0
+}
+
+// mustGather writes the tarball of the diagnostics to destDir and returns its
+// path.
+func mustGather(ctx context.Context, opts mustgather.Options, destDir string) (string, error) {
+	restConfig, err := ctrl.GetConfig()
+	if err != nil {
+		return "", err
+	}
+	c, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return "", err
+	}
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return "", err
+	}
+	collector := &mustgather.Collector{
+		Client:  c,
+		Logs:    mustgather.PodLogs(clientset.CoreV1()),
+		Log:     ctrl.Log.WithName("must-gather"),
+		Options: opts,
+	}
+
+	name := mustgather.Name(time.Now())
+	path := filepath.Join(destDir, name+".tar.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	if err := collector.Collect(ctx, f, name); err != nil {
+		f.Close()
+		os.Remove(path)
+		return "", err
+	}
+	return path, f.Close()
+}
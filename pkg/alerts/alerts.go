@@ -0,0 +1,274 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package alerts maintains the PrometheusRule containing the default alerts
+// for the Clusters managed by the operator.
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	darkowlzzspacev1 "github.com/darkowlzz/hco/api/v1"
+)
+
+// RuleName is the name of the PrometheusRule maintained by the operator.
+const RuleName = "hco-alerts"
+
+// Names of the default alerts.
+const (
+	ClusterDegraded       = "HcoClusterDegraded"
+	ClusterUpgradeStuck   = "HcoClusterUpgradeStuck"
+	ComponentNotReady     = "HcoComponentNotReady"
+	ComponentCrashLooping = "HcoComponentCrashLooping"
+)
+
+// Names is the list of the names of the default alerts.
+var Names = []string{ClusterDegraded, ClusterUpgradeStuck, ComponentNotReady, ComponentCrashLooping}
+
+const (
+	defaultResyncInterval = 10 * time.Minute
+
+	// crashLoopRestartWindow is the window over which container restarts
+	// are counted.
+	crashLoopRestartWindow = "15m"
+)
+
+// podClusterLabel is the label of the kube_pod_labels series of
+// kube-state-metrics holding the ClusterNameLabel of a pod.
+var podClusterLabel = "label_" + strings.NewReplacer(".", "_", "/", "_", "-", "_").Replace(darkowlzzspacev1.ClusterNameLabel)
+
+// prometheusRuleGVK is the kind of the prometheus-operator alerting rules.
+var prometheusRuleGVK = schema.GroupVersionKind{
+	Group:   "monitoring.coreos.com",
+	Version: "v1",
+	Kind:    "PrometheusRule",
+}
+
+// Options configures the alerts.
+type Options struct {
+	// Enabled controls whether the PrometheusRule is maintained. The rule is
+	// deleted when disabled.
+	Enabled bool
+
+	// Namespace is the namespace of the PrometheusRule.
+	Namespace string
+
+	// Disabled is the list of the names of the alerts to leave out.
+	Disabled []string
+
+	// DegradedFor is the time a Cluster has to be degraded before
+	// ClusterDegraded fires.
+	DegradedFor time.Duration
+
+	// UpgradeStuckFor is the time a Cluster has to be progressing before
+	// ClusterUpgradeStuck fires.
+	UpgradeStuckFor time.Duration
+
+	// ComponentNotReadyFor is the time a component has to be not ready
+	// before ComponentNotReady fires.
+	ComponentNotReadyFor time.Duration
+
+	// CrashLoopingFor is the time the containers of the pods of a Cluster
+	// have to keep restarting before ComponentCrashLooping fires.
+	CrashLoopingFor time.Duration
+}
+
+// RuleReconciler keeps the PrometheusRule of the operator up to date. It is
+// a manager Runnable, started only on the elected replica.
+type RuleReconciler struct {
+	Client  client.Client
+	Log     logr.Logger
+	Options Options
+
+	// FieldManager is the field manager of the changes made to the rule.
+	FieldManager string
+
+	// ResyncInterval is the interval at which the rule is reconciled.
+	// Defaults to 10 minutes.
+	ResyncInterval time.Duration
+}
+
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;create;patch;delete
+
+// Start implements manager.Runnable.
+func (r *RuleReconciler) Start(stop <-chan struct{}) error {
+	interval := r.ResyncInterval
+	if interval == 0 {
+		interval = defaultResyncInterval
+	}
+	wait.Until(func() {
+		if err := r.reconcile(context.Background()); err != nil {
+			r.Log.Error(err, "failed to reconcile PrometheusRule")
+		}
+	}, interval, stop)
+	return nil
+}
+
+func (r *RuleReconciler) reconcile(ctx context.Context) error {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(prometheusRuleGVK)
+	key := types.NamespacedName{Namespace: r.Options.Namespace, Name: RuleName}
+	err := r.Client.Get(ctx, key, existing)
+	if meta.IsNoMatchError(err) {
+		r.Log.Info("PrometheusRule kind not available, skipping alerts")
+		return nil
+	}
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	found := err == nil
+
+	// A rule group without rules is invalid, the rule is deleted when all
+	// the alerts are disabled.
+	rules := Rules(r.Options)
+	if !r.Options.Enabled || len(rules) == 0 {
+		if found {
+			r.Log.Info("deleting PrometheusRule", "name", key)
+			return client.IgnoreNotFound(r.Client.Delete(ctx, existing))
+		}
+		return nil
+	}
+
+	spec, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&ruleSpec{Groups: []ruleGroup{{
+		Name:  "hco.rules",
+		Rules: rules,
+	}}})
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		rule := &unstructured.Unstructured{}
+		rule.SetGroupVersionKind(prometheusRuleGVK)
+		rule.SetNamespace(key.Namespace)
+		rule.SetName(key.Name)
+		rule.SetLabels(map[string]string{"control-plane": "controller-manager"})
+		rule.Object["spec"] = spec
+		r.Log.Info("creating PrometheusRule", "name", key)
+		return r.Client.Create(ctx, rule, client.FieldOwner(r.FieldManager))
+	}
+	if equality.Semantic.DeepEqual(existing.Object["spec"], spec) {
+		return nil
+	}
+	patch := client.MergeFrom(existing.DeepCopy())
+	existing.Object["spec"] = spec
+	r.Log.Info("updating PrometheusRule", "name", key)
+	return r.Client.Patch(ctx, existing, patch, client.FieldOwner(r.FieldManager))
+}
+
+// ruleSpec is the spec of a PrometheusRule.
+type ruleSpec struct {
+	Groups []ruleGroup `json:"groups"`
+}
+
+// ruleGroup is a group of alerting rules.
+type ruleGroup struct {
+	Name  string `json:"name"`
+	Rules []Rule `json:"rules"`
+}
+
+// Rule is an alerting rule.
+type Rule struct {
+	Alert       string            `json:"alert"`
+	Expr        string            `json:"expr"`
+	For         string            `json:"for,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Rules returns the enabled alerting rules for the given options.
+func Rules(opts Options) []Rule {
+	all := []Rule{
+		{
+			Alert:  ClusterDegraded,
+			Expr:   `hco_cluster_condition{type="Degraded",status="True"} == 1`,
+			For:    duration(opts.DegradedFor),
+			Labels: map[string]string{"severity": "warning"},
+			Annotations: map[string]string{
+				"summary":     "Cluster is degraded.",
+				"description": "Cluster {{ $labels.namespace }}/{{ $labels.name }} has been degraded for more than " + duration(opts.DegradedFor) + ".",
+			},
+		},
+		{
+			Alert:  ClusterUpgradeStuck,
+			Expr:   `hco_cluster_condition{type="Progressing",status="True"} == 1`,
+			For:    duration(opts.UpgradeStuckFor),
+			Labels: map[string]string{"severity": "warning"},
+			Annotations: map[string]string{
+				"summary":     "Cluster upgrade is not progressing.",
+				"description": "Cluster {{ $labels.namespace }}/{{ $labels.name }} has been upgrading for more than " + duration(opts.UpgradeStuckFor) + ".",
+			},
+		},
+		{
+			Alert:  ComponentNotReady,
+			Expr:   `hco_cluster_component_ready == 0`,
+			For:    duration(opts.ComponentNotReadyFor),
+			Labels: map[string]string{"severity": "warning"},
+			Annotations: map[string]string{
+				"summary":     "Cluster component is not ready.",
+				"description": "Component {{ $labels.component }} of Cluster {{ $labels.namespace }}/{{ $labels.name }} has not been ready for more than " + duration(opts.ComponentNotReadyFor) + ".",
+			},
+		},
+		{
+			// Only the pods labeled with the name of a Cluster are
+			// counted. kube-state-metrics has to expose the label, see
+			// its --metric-labels-allowlist flag.
+			Alert: ComponentCrashLooping,
+			Expr: fmt.Sprintf(`increase(kube_pod_container_status_restarts_total[%s])`+
+				` * on (namespace, pod) group_left (name) max by (namespace, pod, name) (`+
+				`label_replace(kube_pod_labels{%s!=""}, "name", "$1", "%s", "(.*)")) > 3`,
+				crashLoopRestartWindow, podClusterLabel, podClusterLabel),
+			For:    duration(opts.CrashLoopingFor),
+			Labels: map[string]string{"severity": "warning"},
+			Annotations: map[string]string{
+				"summary":     "Cluster container is crash looping.",
+				"description": "Container {{ $labels.container }} of pod {{ $labels.pod }} of Cluster {{ $labels.namespace }}/{{ $labels.name }} restarted more than 3 times in the last " + crashLoopRestartWindow + ".",
+			},
+		},
+	}
+
+	disabled := map[string]bool{}
+	for _, name := range opts.Disabled {
+		disabled[name] = true
+	}
+	var rules []Rule
+	for _, rule := range all {
+		if !disabled[rule.Alert] {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// duration formats d in the Prometheus duration format.
+func duration(d time.Duration) string {
+	return model.Duration(d).String()
+}
@@ -0,0 +1,118 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alerts
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// TestRules checks that the disabled alerts are left out.
+func TestRules(t *testing.T) {
+	rules := Rules(Options{Disabled: []string{ClusterDegraded, ComponentNotReady}})
+	var names []string
+	for _, rule := range rules {
+		names = append(names, rule.Alert)
+	}
+	if got, want := strings.Join(names, ","), ClusterUpgradeStuck+","+ComponentCrashLooping; got != want {
+		t.Errorf("expected the alerts %s, got %s", want, got)
+	}
+
+	// The restarts are only counted for the pods of a Cluster.
+	expr := rules[1].Expr
+	if !strings.Contains(expr, `kube_pod_labels{label_darkowlzz_space_cluster!=""}`) {
+		t.Errorf("expected the restarts to be restricted to the pods of a Cluster, got %s", expr)
+	}
+}
+
+// TestReconcileAllDisabled checks that the rule is deleted rather than left
+// without rules when all the alerts are disabled.
+func TestReconcileAllDisabled(t *testing.T) {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(prometheusRuleGVK)
+	existing.SetNamespace("hco-system")
+	existing.SetName(RuleName)
+	c := fake.NewFakeClientWithScheme(runtime.NewScheme(), existing)
+
+	r := &RuleReconciler{
+		Client:  c,
+		Log:     log.NullLogger{},
+		Options: Options{Enabled: true, Namespace: "hco-system", Disabled: Names},
+	}
+	if err := r.reconcile(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	rule := &unstructured.Unstructured{}
+	rule.SetGroupVersionKind(prometheusRuleGVK)
+	err := c.Get(context.Background(), types.NamespacedName{Namespace: "hco-system", Name: RuleName}, rule)
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected the PrometheusRule to be deleted, got %v", err)
+	}
+}
+
+// ownerClient records the field owner of the creates and patches.
+type ownerClient struct {
+	client.Client
+	owners []string
+}
+
+func (c *ownerClient) Create(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+	createOpts := &client.CreateOptions{}
+	createOpts.ApplyOptions(opts)
+	c.owners = append(c.owners, createOpts.FieldManager)
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *ownerClient) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	patchOpts := &client.PatchOptions{}
+	patchOpts.ApplyOptions(opts)
+	c.owners = append(c.owners, patchOpts.FieldManager)
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+// TestReconcileFieldManager checks that the rule is created and updated
+// with the field manager of the operator.
+func TestReconcileFieldManager(t *testing.T) {
+	c := &ownerClient{Client: fake.NewFakeClientWithScheme(runtime.NewScheme())}
+	r := &RuleReconciler{
+		Client:       c,
+		Log:          log.NullLogger{},
+		Options:      Options{Enabled: true, Namespace: "hco-system"},
+		FieldManager: "hco-controller-manager",
+	}
+	if err := r.reconcile(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	r.Options.Disabled = []string{ClusterDegraded}
+	if err := r.reconcile(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := strings.Join(c.owners, ","), "hco-controller-manager,hco-controller-manager"; got != want {
+		t.Errorf("expected the field managers %s, got %s", want, got)
+	}
+}
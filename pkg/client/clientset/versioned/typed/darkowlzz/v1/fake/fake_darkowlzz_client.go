@@ -0,0 +1,51 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v1 "github.com/darkowlzz/hco/pkg/client/clientset/versioned/typed/darkowlzz/v1"
+	rest "k8s.io/client-go/rest"
+	testing "k8s.io/client-go/testing"
+)
+
+type FakeDarkowlzzV1 struct {
+	*testing.Fake
+}
+
+func (c *FakeDarkowlzzV1) Apps(namespace string) v1.AppInterface {
+	return &FakeApps{c, namespace}
+}
+
+func (c *FakeDarkowlzzV1) Clusters(namespace string) v1.ClusterInterface {
+	return &FakeClusters{c, namespace}
+}
+
+func (c *FakeDarkowlzzV1) SidecarAs(namespace string) v1.SidecarAInterface {
+	return &FakeSidecarAs{c, namespace}
+}
+
+func (c *FakeDarkowlzzV1) SidecarBs(namespace string) v1.SidecarBInterface {
+	return &FakeSidecarBs{c, namespace}
+}
+
+// RESTClient returns a RESTClient that is used to communicate
+// with API server by this client implementation.
+func (c *FakeDarkowlzzV1) RESTClient() rest.Interface {
+	var ret *rest.RESTClient
+	return ret
+}
@@ -0,0 +1,141 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	darkowlzzv1 "github.com/darkowlzz/hco/api/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeSidecarAs implements SidecarAInterface
+type FakeSidecarAs struct {
+	Fake *FakeDarkowlzzV1
+	ns   string
+}
+
+var sidecarasResource = schema.GroupVersionResource{Group: "darkowlzz.space", Version: "v1", Resource: "sidecaras"}
+
+var sidecarasKind = schema.GroupVersionKind{Group: "darkowlzz.space", Version: "v1", Kind: "SidecarA"}
+
+// Get takes name of the sidecarA, and returns the corresponding sidecarA object, and an error if there is any.
+func (c *FakeSidecarAs) Get(ctx context.Context, name string, options v1.GetOptions) (result *darkowlzzv1.SidecarA, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(sidecarasResource, c.ns, name), &darkowlzzv1.SidecarA{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*darkowlzzv1.SidecarA), err
+}
+
+// List takes label and field selectors, and returns the list of SidecarAs that match those selectors.
+func (c *FakeSidecarAs) List(ctx context.Context, opts v1.ListOptions) (result *darkowlzzv1.SidecarAList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(sidecarasResource, sidecarasKind, c.ns, opts), &darkowlzzv1.SidecarAList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &darkowlzzv1.SidecarAList{ListMeta: obj.(*darkowlzzv1.SidecarAList).ListMeta}
+	for _, item := range obj.(*darkowlzzv1.SidecarAList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested sidecarAs.
+func (c *FakeSidecarAs) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(sidecarasResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a sidecarA and creates it.  Returns the server's representation of the sidecarA, and an error, if there is any.
+func (c *FakeSidecarAs) Create(ctx context.Context, sidecarA *darkowlzzv1.SidecarA, opts v1.CreateOptions) (result *darkowlzzv1.SidecarA, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(sidecarasResource, c.ns, sidecarA), &darkowlzzv1.SidecarA{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*darkowlzzv1.SidecarA), err
+}
+
+// Update takes the representation of a sidecarA and updates it. Returns the server's representation of the sidecarA, and an error, if there is any.
+func (c *FakeSidecarAs) Update(ctx context.Context, sidecarA *darkowlzzv1.SidecarA, opts v1.UpdateOptions) (result *darkowlzzv1.SidecarA, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(sidecarasResource, c.ns, sidecarA), &darkowlzzv1.SidecarA{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*darkowlzzv1.SidecarA), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeSidecarAs) UpdateStatus(ctx context.Context, sidecarA *darkowlzzv1.SidecarA, opts v1.UpdateOptions) (*darkowlzzv1.SidecarA, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(sidecarasResource, "status", c.ns, sidecarA), &darkowlzzv1.SidecarA{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*darkowlzzv1.SidecarA), err
+}
+
+// Delete takes name of the sidecarA and deletes it. Returns an error if one occurs.
+func (c *FakeSidecarAs) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(sidecarasResource, c.ns, name), &darkowlzzv1.SidecarA{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeSidecarAs) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(sidecarasResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &darkowlzzv1.SidecarAList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched sidecarA.
+func (c *FakeSidecarAs) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *darkowlzzv1.SidecarA, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(sidecarasResource, c.ns, name, pt, data, subresources...), &darkowlzzv1.SidecarA{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*darkowlzzv1.SidecarA), err
+}
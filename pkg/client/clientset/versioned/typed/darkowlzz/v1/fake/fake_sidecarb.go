@@ -0,0 +1,141 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	darkowlzzv1 "github.com/darkowlzz/hco/api/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeSidecarBs implements SidecarBInterface
+type FakeSidecarBs struct {
+	Fake *FakeDarkowlzzV1
+	ns   string
+}
+
+var sidecarbsResource = schema.GroupVersionResource{Group: "darkowlzz.space", Version: "v1", Resource: "sidecarbs"}
+
+var sidecarbsKind = schema.GroupVersionKind{Group: "darkowlzz.space", Version: "v1", Kind: "SidecarB"}
+
+// Get takes name of the sidecarB, and returns the corresponding sidecarB object, and an error if there is any.
+func (c *FakeSidecarBs) Get(ctx context.Context, name string, options v1.GetOptions) (result *darkowlzzv1.SidecarB, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(sidecarbsResource, c.ns, name), &darkowlzzv1.SidecarB{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*darkowlzzv1.SidecarB), err
+}
+
+// List takes label and field selectors, and returns the list of SidecarBs that match those selectors.
+func (c *FakeSidecarBs) List(ctx context.Context, opts v1.ListOptions) (result *darkowlzzv1.SidecarBList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(sidecarbsResource, sidecarbsKind, c.ns, opts), &darkowlzzv1.SidecarBList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &darkowlzzv1.SidecarBList{ListMeta: obj.(*darkowlzzv1.SidecarBList).ListMeta}
+	for _, item := range obj.(*darkowlzzv1.SidecarBList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested sidecarBs.
+func (c *FakeSidecarBs) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(sidecarbsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a sidecarB and creates it.  Returns the server's representation of the sidecarB, and an error, if there is any.
+func (c *FakeSidecarBs) Create(ctx context.Context, sidecarB *darkowlzzv1.SidecarB, opts v1.CreateOptions) (result *darkowlzzv1.SidecarB, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(sidecarbsResource, c.ns, sidecarB), &darkowlzzv1.SidecarB{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*darkowlzzv1.SidecarB), err
+}
+
+// Update takes the representation of a sidecarB and updates it. Returns the server's representation of the sidecarB, and an error, if there is any.
+func (c *FakeSidecarBs) Update(ctx context.Context, sidecarB *darkowlzzv1.SidecarB, opts v1.UpdateOptions) (result *darkowlzzv1.SidecarB, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(sidecarbsResource, c.ns, sidecarB), &darkowlzzv1.SidecarB{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*darkowlzzv1.SidecarB), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeSidecarBs) UpdateStatus(ctx context.Context, sidecarB *darkowlzzv1.SidecarB, opts v1.UpdateOptions) (*darkowlzzv1.SidecarB, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(sidecarbsResource, "status", c.ns, sidecarB), &darkowlzzv1.SidecarB{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*darkowlzzv1.SidecarB), err
+}
+
+// Delete takes name of the sidecarB and deletes it. Returns an error if one occurs.
+func (c *FakeSidecarBs) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(sidecarbsResource, c.ns, name), &darkowlzzv1.SidecarB{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeSidecarBs) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(sidecarbsResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &darkowlzzv1.SidecarBList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched sidecarB.
+func (c *FakeSidecarBs) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *darkowlzzv1.SidecarB, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(sidecarbsResource, c.ns, name, pt, data, subresources...), &darkowlzzv1.SidecarB{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*darkowlzzv1.SidecarB), err
+}
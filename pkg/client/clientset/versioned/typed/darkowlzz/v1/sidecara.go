@@ -0,0 +1,194 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/darkowlzz/hco/api/v1"
+	scheme "github.com/darkowlzz/hco/pkg/client/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// SidecarAsGetter has a method to return a SidecarAInterface.
+// A group's client should implement this interface.
+type SidecarAsGetter interface {
+	SidecarAs(namespace string) SidecarAInterface
+}
+
+// SidecarAInterface has methods to work with SidecarA resources.
+type SidecarAInterface interface {
+	Create(ctx context.Context, sidecarA *v1.SidecarA, opts metav1.CreateOptions) (*v1.SidecarA, error)
+	Update(ctx context.Context, sidecarA *v1.SidecarA, opts metav1.UpdateOptions) (*v1.SidecarA, error)
+	UpdateStatus(ctx context.Context, sidecarA *v1.SidecarA, opts metav1.UpdateOptions) (*v1.SidecarA, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.SidecarA, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.SidecarAList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.SidecarA, err error)
+	SidecarAExpansion
+}
+
+// sidecarAs implements SidecarAInterface
+type sidecarAs struct {
+	client rest.Interface
+	ns     string
+}
+
+// newSidecarAs returns a SidecarAs
+func newSidecarAs(c *DarkowlzzV1Client, namespace string) *sidecarAs {
+	return &sidecarAs{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the sidecarA, and returns the corresponding sidecarA object, and an error if there is any.
+func (c *sidecarAs) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.SidecarA, err error) {
+	result = &v1.SidecarA{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("sidecaras").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of SidecarAs that match those selectors.
+func (c *sidecarAs) List(ctx context.Context, opts metav1.ListOptions) (result *v1.SidecarAList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.SidecarAList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("sidecaras").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested sidecarAs.
+func (c *sidecarAs) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("sidecaras").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a sidecarA and creates it.  Returns the server's representation of the sidecarA, and an error, if there is any.
+func (c *sidecarAs) Create(ctx context.Context, sidecarA *v1.SidecarA, opts metav1.CreateOptions) (result *v1.SidecarA, err error) {
+	result = &v1.SidecarA{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("sidecaras").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(sidecarA).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a sidecarA and updates it. Returns the server's representation of the sidecarA, and an error, if there is any.
+func (c *sidecarAs) Update(ctx context.Context, sidecarA *v1.SidecarA, opts metav1.UpdateOptions) (result *v1.SidecarA, err error) {
+	result = &v1.SidecarA{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("sidecaras").
+		Name(sidecarA.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(sidecarA).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *sidecarAs) UpdateStatus(ctx context.Context, sidecarA *v1.SidecarA, opts metav1.UpdateOptions) (result *v1.SidecarA, err error) {
+	result = &v1.SidecarA{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("sidecaras").
+		Name(sidecarA.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(sidecarA).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the sidecarA and deletes it. Returns an error if one occurs.
+func (c *sidecarAs) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("sidecaras").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *sidecarAs) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("sidecaras").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched sidecarA.
+func (c *sidecarAs) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.SidecarA, err error) {
+	result = &v1.SidecarA{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("sidecaras").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
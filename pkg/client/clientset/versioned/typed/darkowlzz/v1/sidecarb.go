@@ -0,0 +1,194 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/darkowlzz/hco/api/v1"
+	scheme "github.com/darkowlzz/hco/pkg/client/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// SidecarBsGetter has a method to return a SidecarBInterface.
+// A group's client should implement this interface.
+type SidecarBsGetter interface {
+	SidecarBs(namespace string) SidecarBInterface
+}
+
+// SidecarBInterface has methods to work with SidecarB resources.
+type SidecarBInterface interface {
+	Create(ctx context.Context, sidecarB *v1.SidecarB, opts metav1.CreateOptions) (*v1.SidecarB, error)
+	Update(ctx context.Context, sidecarB *v1.SidecarB, opts metav1.UpdateOptions) (*v1.SidecarB, error)
+	UpdateStatus(ctx context.Context, sidecarB *v1.SidecarB, opts metav1.UpdateOptions) (*v1.SidecarB, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.SidecarB, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.SidecarBList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.SidecarB, err error)
+	SidecarBExpansion
+}
+
+// sidecarBs implements SidecarBInterface
+type sidecarBs struct {
+	client rest.Interface
+	ns     string
+}
+
+// newSidecarBs returns a SidecarBs
+func newSidecarBs(c *DarkowlzzV1Client, namespace string) *sidecarBs {
+	return &sidecarBs{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the sidecarB, and returns the corresponding sidecarB object, and an error if there is any.
+func (c *sidecarBs) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.SidecarB, err error) {
+	result = &v1.SidecarB{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("sidecarbs").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of SidecarBs that match those selectors.
+func (c *sidecarBs) List(ctx context.Context, opts metav1.ListOptions) (result *v1.SidecarBList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.SidecarBList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("sidecarbs").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested sidecarBs.
+func (c *sidecarBs) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("sidecarbs").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a sidecarB and creates it.  Returns the server's representation of the sidecarB, and an error, if there is any.
+func (c *sidecarBs) Create(ctx context.Context, sidecarB *v1.SidecarB, opts metav1.CreateOptions) (result *v1.SidecarB, err error) {
+	result = &v1.SidecarB{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("sidecarbs").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(sidecarB).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a sidecarB and updates it. Returns the server's representation of the sidecarB, and an error, if there is any.
+func (c *sidecarBs) Update(ctx context.Context, sidecarB *v1.SidecarB, opts metav1.UpdateOptions) (result *v1.SidecarB, err error) {
+	result = &v1.SidecarB{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("sidecarbs").
+		Name(sidecarB.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(sidecarB).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *sidecarBs) UpdateStatus(ctx context.Context, sidecarB *v1.SidecarB, opts metav1.UpdateOptions) (result *v1.SidecarB, err error) {
+	result = &v1.SidecarB{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("sidecarbs").
+		Name(sidecarB.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(sidecarB).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the sidecarB and deletes it. Returns an error if one occurs.
+func (c *sidecarBs) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("sidecarbs").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *sidecarBs) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("sidecarbs").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched sidecarB.
+func (c *sidecarBs) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.SidecarB, err error) {
+	result = &v1.SidecarB{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("sidecarbs").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
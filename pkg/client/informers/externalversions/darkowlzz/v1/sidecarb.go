@@ -0,0 +1,89 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	time "time"
+
+	darkowlzzv1 "github.com/darkowlzz/hco/api/v1"
+	versioned "github.com/darkowlzz/hco/pkg/client/clientset/versioned"
+	internalinterfaces "github.com/darkowlzz/hco/pkg/client/informers/externalversions/internalinterfaces"
+	v1 "github.com/darkowlzz/hco/pkg/client/listers/darkowlzz/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// SidecarBInformer provides access to a shared informer and lister for
+// SidecarBs.
+type SidecarBInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1.SidecarBLister
+}
+
+type sidecarBInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	namespace        string
+}
+
+// NewSidecarBInformer constructs a new informer for SidecarB type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewSidecarBInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredSidecarBInformer(client, namespace, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredSidecarBInformer constructs a new informer for SidecarB type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredSidecarBInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.DarkowlzzV1().SidecarBs(namespace).List(context.TODO(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.DarkowlzzV1().SidecarBs(namespace).Watch(context.TODO(), options)
+			},
+		},
+		&darkowlzzv1.SidecarB{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *sidecarBInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredSidecarBInformer(client, f.namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *sidecarBInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&darkowlzzv1.SidecarB{}, f.defaultInformer)
+}
+
+func (f *sidecarBInformer) Lister() v1.SidecarBLister {
+	return v1.NewSidecarBLister(f.Informer().GetIndexer())
+}
@@ -0,0 +1,50 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+// AppListerExpansion allows custom methods to be added to
+// AppLister.
+type AppListerExpansion interface{}
+
+// AppNamespaceListerExpansion allows custom methods to be added to
+// AppNamespaceLister.
+type AppNamespaceListerExpansion interface{}
+
+// ClusterListerExpansion allows custom methods to be added to
+// ClusterLister.
+type ClusterListerExpansion interface{}
+
+// ClusterNamespaceListerExpansion allows custom methods to be added to
+// ClusterNamespaceLister.
+type ClusterNamespaceListerExpansion interface{}
+
+// SidecarAListerExpansion allows custom methods to be added to
+// SidecarALister.
+type SidecarAListerExpansion interface{}
+
+// SidecarANamespaceListerExpansion allows custom methods to be added to
+// SidecarANamespaceLister.
+type SidecarANamespaceListerExpansion interface{}
+
+// SidecarBListerExpansion allows custom methods to be added to
+// SidecarBLister.
+type SidecarBListerExpansion interface{}
+
+// SidecarBNamespaceListerExpansion allows custom methods to be added to
+// SidecarBNamespaceLister.
+type SidecarBNamespaceListerExpansion interface{}
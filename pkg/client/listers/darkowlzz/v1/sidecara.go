@@ -0,0 +1,93 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/darkowlzz/hco/api/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// SidecarALister helps list SidecarAs.
+type SidecarALister interface {
+	// List lists all SidecarAs in the indexer.
+	List(selector labels.Selector) (ret []*v1.SidecarA, err error)
+	// SidecarAs returns an object that can list and get SidecarAs.
+	SidecarAs(namespace string) SidecarANamespaceLister
+	SidecarAListerExpansion
+}
+
+// sidecarALister implements the SidecarALister interface.
+type sidecarALister struct {
+	indexer cache.Indexer
+}
+
+// NewSidecarALister returns a new SidecarALister.
+func NewSidecarALister(indexer cache.Indexer) SidecarALister {
+	return &sidecarALister{indexer: indexer}
+}
+
+// List lists all SidecarAs in the indexer.
+func (s *sidecarALister) List(selector labels.Selector) (ret []*v1.SidecarA, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.SidecarA))
+	})
+	return ret, err
+}
+
+// SidecarAs returns an object that can list and get SidecarAs.
+func (s *sidecarALister) SidecarAs(namespace string) SidecarANamespaceLister {
+	return sidecarANamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// SidecarANamespaceLister helps list and get SidecarAs.
+type SidecarANamespaceLister interface {
+	// List lists all SidecarAs in the indexer for a given namespace.
+	List(selector labels.Selector) (ret []*v1.SidecarA, err error)
+	// Get retrieves the SidecarA from the indexer for a given namespace and name.
+	Get(name string) (*v1.SidecarA, error)
+	SidecarANamespaceListerExpansion
+}
+
+// sidecarANamespaceLister implements the SidecarANamespaceLister
+// interface.
+type sidecarANamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all SidecarAs in the indexer for a given namespace.
+func (s sidecarANamespaceLister) List(selector labels.Selector) (ret []*v1.SidecarA, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.SidecarA))
+	})
+	return ret, err
+}
+
+// Get retrieves the SidecarA from the indexer for a given namespace and name.
+func (s sidecarANamespaceLister) Get(name string) (*v1.SidecarA, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("sidecara"), name)
+	}
+	return obj.(*v1.SidecarA), nil
+}
@@ -0,0 +1,93 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/darkowlzz/hco/api/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// SidecarBLister helps list SidecarBs.
+type SidecarBLister interface {
+	// List lists all SidecarBs in the indexer.
+	List(selector labels.Selector) (ret []*v1.SidecarB, err error)
+	// SidecarBs returns an object that can list and get SidecarBs.
+	SidecarBs(namespace string) SidecarBNamespaceLister
+	SidecarBListerExpansion
+}
+
+// sidecarBLister implements the SidecarBLister interface.
+type sidecarBLister struct {
+	indexer cache.Indexer
+}
+
+// NewSidecarBLister returns a new SidecarBLister.
+func NewSidecarBLister(indexer cache.Indexer) SidecarBLister {
+	return &sidecarBLister{indexer: indexer}
+}
+
+// List lists all SidecarBs in the indexer.
+func (s *sidecarBLister) List(selector labels.Selector) (ret []*v1.SidecarB, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.SidecarB))
+	})
+	return ret, err
+}
+
+// SidecarBs returns an object that can list and get SidecarBs.
+func (s *sidecarBLister) SidecarBs(namespace string) SidecarBNamespaceLister {
+	return sidecarBNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// SidecarBNamespaceLister helps list and get SidecarBs.
+type SidecarBNamespaceLister interface {
+	// List lists all SidecarBs in the indexer for a given namespace.
+	List(selector labels.Selector) (ret []*v1.SidecarB, err error)
+	// Get retrieves the SidecarB from the indexer for a given namespace and name.
+	Get(name string) (*v1.SidecarB, error)
+	SidecarBNamespaceListerExpansion
+}
+
+// sidecarBNamespaceLister implements the SidecarBNamespaceLister
+// interface.
+type sidecarBNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all SidecarBs in the indexer for a given namespace.
+func (s sidecarBNamespaceLister) List(selector labels.Selector) (ret []*v1.SidecarB, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.SidecarB))
+	})
+	return ret, err
+}
+
+// Get retrieves the SidecarB from the indexer for a given namespace and name.
+func (s sidecarBNamespaceLister) Get(name string) (*v1.SidecarB, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("sidecarb"), name)
+	}
+	return obj.(*v1.SidecarB), nil
+}
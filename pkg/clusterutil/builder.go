@@ -0,0 +1,122 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clusterutil contains helpers to build Clusters and to wait on
+// their conditions.
+package clusterutil
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	darkowlzzspacev1 "github.com/darkowlzz/hco/api/v1"
+)
+
+// ClusterBuilder builds a Cluster.
+type ClusterBuilder struct {
+	cluster darkowlzzspacev1.Cluster
+}
+
+// NewCluster returns a builder of a Cluster with the given namespace and
+// name.
+func NewCluster(namespace, name string) *ClusterBuilder {
+	return &ClusterBuilder{cluster: darkowlzzspacev1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: darkowlzzspacev1.GroupVersion.String(),
+			Kind:       "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+	}}
+}
+
+// WithImages sets the images of the components of the Cluster.
+func (b *ClusterBuilder) WithImages(images darkowlzzspacev1.ImageReference) *ClusterBuilder {
+	b.cluster.Spec.Images = images
+	return b
+}
+
+// WithLogLevel sets the log level of the Cluster, one of info, debug or
+// error.
+func (b *ClusterBuilder) WithLogLevel(level string) *ClusterBuilder {
+	b.cluster.Spec.LogLevel = level
+	return b
+}
+
+// WithLabels adds labels to the Cluster. The labels are propagated to the
+// children of the Cluster.
+func (b *ClusterBuilder) WithLabels(labels map[string]string) *ClusterBuilder {
+	if b.cluster.Labels == nil {
+		b.cluster.Labels = map[string]string{}
+	}
+	for k, v := range labels {
+		b.cluster.Labels[k] = v
+	}
+	return b
+}
+
+// Paused sets whether the reconciliation of the components of the Cluster
+// is paused.
+func (b *ClusterBuilder) Paused(paused bool) *ClusterBuilder {
+	if !paused {
+		delete(b.cluster.Annotations, darkowlzzspacev1.PausedAnnotation)
+		return b
+	}
+	if b.cluster.Annotations == nil {
+		b.cluster.Annotations = map[string]string{}
+	}
+	b.cluster.Annotations[darkowlzzspacev1.PausedAnnotation] = "true"
+	return b
+}
+
+// Build returns the Cluster. The builder can be reused afterwards.
+func (b *ClusterBuilder) Build() *darkowlzzspacev1.Cluster {
+	return b.cluster.DeepCopy()
+}
+
+// ImageReferenceBuilder builds an ImageReference.
+type ImageReferenceBuilder struct {
+	images darkowlzzspacev1.ImageReference
+}
+
+// NewImageReference returns a builder of an empty ImageReference.
+func NewImageReference() *ImageReferenceBuilder {
+	return &ImageReferenceBuilder{}
+}
+
+// WithApp sets the image of the app.
+func (b *ImageReferenceBuilder) WithApp(image string) *ImageReferenceBuilder {
+	b.images.App = image
+	return b
+}
+
+// WithSidecarA sets the image of sidecarA.
+func (b *ImageReferenceBuilder) WithSidecarA(image string) *ImageReferenceBuilder {
+	b.images.SidecarA = image
+	return b
+}
+
+// WithSidecarB sets the image of sidecarB.
+func (b *ImageReferenceBuilder) WithSidecarB(image string) *ImageReferenceBuilder {
+	b.images.SidecarB = image
+	return b
+}
+
+// Build returns the ImageReference.
+func (b *ImageReferenceBuilder) Build() darkowlzzspacev1.ImageReference {
+	return b.images
+}
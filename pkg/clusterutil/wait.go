@@ -0,0 +1,118 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterutil
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	conditions "github.com/openshift/custom-resource-status/conditions/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	darkowlzzspacev1 "github.com/darkowlzz/hco/api/v1"
+)
+
+// PollInterval is the interval at which the Cluster is read while waiting.
+var PollInterval = 2 * time.Second
+
+// DegradedError is returned when a Cluster becomes Degraded while waiting on
+// it.
+type DegradedError struct {
+	Key     client.ObjectKey
+	Reason  string
+	Message string
+}
+
+func (e *DegradedError) Error() string {
+	return fmt.Sprintf("cluster %s is degraded: %s: %s", e.Key, e.Reason, e.Message)
+}
+
+// WaitForAvailable waits until the Cluster with the given key is Available
+// and returns it. It fails with a DegradedError as soon as the Cluster is
+// Degraded, and with the last observed state when ctx is done.
+func WaitForAvailable(ctx context.Context, c client.Reader, key client.ObjectKey) (*darkowlzzspacev1.Cluster, error) {
+	return waitFor(ctx, c, key, "available", func(cluster *darkowlzzspacev1.Cluster) bool {
+		return conditions.IsStatusConditionTrue(cluster.Status.Conditions, conditions.ConditionAvailable)
+	})
+}
+
+// WaitForUpgrade waits until the Cluster with the given key has rolled out
+// its latest spec to all its components and returns it. It fails with a
+// DegradedError as soon as the Cluster is Degraded, and with the last
+// observed state when ctx is done. A paused Cluster does not complete its
+// upgrade until it is resumed.
+func WaitForUpgrade(ctx context.Context, c client.Reader, key client.ObjectKey) (*darkowlzzspacev1.Cluster, error) {
+	return waitFor(ctx, c, key, "upgraded", func(cluster *darkowlzzspacev1.Cluster) bool {
+		if cluster.Status.ObservedGeneration != cluster.Generation {
+			return false
+		}
+		progressing := conditions.FindStatusCondition(cluster.Status.Conditions, conditions.ConditionProgressing)
+		return conditions.IsStatusConditionTrue(cluster.Status.Conditions, conditions.ConditionAvailable) &&
+			progressing != nil && progressing.Status == corev1.ConditionFalse &&
+			progressing.Reason == darkowlzzspacev1.ReasonUpgradeComplete
+	})
+}
+
+// waitFor polls the Cluster with the given key until done returns true.
+func waitFor(ctx context.Context, c client.Reader, key client.ObjectKey, state string, done func(*darkowlzzspacev1.Cluster) bool) (*darkowlzzspacev1.Cluster, error) {
+	var cluster *darkowlzzspacev1.Cluster
+	var lastErr error
+	err := wait.PollImmediateUntil(PollInterval, func() (bool, error) {
+		current := &darkowlzzspacev1.Cluster{}
+		if err := c.Get(ctx, key, current); err != nil {
+			// The Cluster may not be in the cache yet, keep polling.
+			lastErr = err
+			return false, nil
+		}
+		cluster, lastErr = current, nil
+		if degraded := conditions.FindStatusCondition(current.Status.Conditions, conditions.ConditionDegraded); degraded != nil &&
+			degraded.Status == corev1.ConditionTrue {
+			return false, &DegradedError{Key: key, Reason: degraded.Reason, Message: degraded.Message}
+		}
+		return done(current), nil
+	}, ctx.Done())
+	if err == wait.ErrWaitTimeout {
+		switch {
+		case lastErr != nil:
+			err = fmt.Errorf("timed out waiting for cluster %s to be %s: %w", key, state, lastErr)
+		case cluster == nil:
+			err = fmt.Errorf("timed out waiting for cluster %s to be %s", key, state)
+		default:
+			err = fmt.Errorf("timed out waiting for cluster %s to be %s, last conditions: %s",
+				key, state, describeConditions(cluster.Status.Conditions))
+		}
+	}
+	return cluster, err
+}
+
+// describeConditions formats conditions as a short human readable list.
+func describeConditions(conds []conditions.Condition) string {
+	if len(conds) == 0 {
+		return "none"
+	}
+	var s string
+	for i, cond := range conds {
+		if i > 0 {
+			s += ", "
+		}
+		s += fmt.Sprintf("%s=%s (%s)", cond.Type, cond.Status, cond.Reason)
+	}
+	return s
+}
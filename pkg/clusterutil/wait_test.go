@@ -0,0 +1,148 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterutil
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	conditions "github.com/openshift/custom-resource-status/conditions/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	darkowlzzspacev1 "github.com/darkowlzz/hco/api/v1"
+)
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	if err := darkowlzzspacev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return scheme
+}
+
+func withConditions(conds ...conditions.Condition) *darkowlzzspacev1.Cluster {
+	cluster := NewCluster("default", "example").Build()
+	cluster.Status.Conditions = conds
+	return cluster
+}
+
+// shortTimeout polls quickly for a short time, the returned function
+// restores the poll interval.
+func shortTimeout() (context.Context, func()) {
+	interval := PollInterval
+	PollInterval = 10 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	return ctx, func() {
+		cancel()
+		PollInterval = interval
+	}
+}
+
+var key = types.NamespacedName{Namespace: "default", Name: "example"}
+
+// TestWaitDegraded checks that waiting stops as soon as the Cluster is
+// Degraded.
+func TestWaitDegraded(t *testing.T) {
+	c := fake.NewFakeClientWithScheme(newScheme(t), withConditions(conditions.Condition{
+		Type:    conditions.ConditionDegraded,
+		Status:  corev1.ConditionTrue,
+		Reason:  "ComponentFailed",
+		Message: "app is failing",
+	}))
+
+	ctx, done := shortTimeout()
+	defer done()
+	_, err := WaitForAvailable(ctx, c, key)
+	var degraded *DegradedError
+	if !errors.As(err, &degraded) {
+		t.Fatalf("expected a DegradedError, got %v", err)
+	}
+	if degraded.Key != key || degraded.Reason != "ComponentFailed" || degraded.Message != "app is failing" {
+		t.Errorf("unexpected DegradedError %+v", degraded)
+	}
+}
+
+// TestWaitUpgrade checks that an upgrade is complete once the latest
+// generation is observed and Progressing is False.
+func TestWaitUpgrade(t *testing.T) {
+	c := fake.NewFakeClientWithScheme(newScheme(t), withConditions(conditions.Condition{
+		Type:   conditions.ConditionAvailable,
+		Status: corev1.ConditionTrue,
+	}, conditions.Condition{
+		Type:   conditions.ConditionProgressing,
+		Status: corev1.ConditionFalse,
+		Reason: darkowlzzspacev1.ReasonUpgradeComplete,
+	}))
+
+	ctx, done := shortTimeout()
+	defer done()
+	cluster, err := WaitForUpgrade(ctx, c, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cluster.Name != key.Name {
+		t.Errorf("expected the Cluster %s, got %s", key.Name, cluster.Name)
+	}
+}
+
+// TestWaitTimeout checks that a timeout reports the last observed state of
+// the Cluster.
+func TestWaitTimeout(t *testing.T) {
+	c := fake.NewFakeClientWithScheme(newScheme(t), withConditions(conditions.Condition{
+		Type:   conditions.ConditionAvailable,
+		Status: corev1.ConditionFalse,
+		Reason: "Reconciling",
+	}))
+
+	ctx, done := shortTimeout()
+	defer done()
+	_, err := WaitForAvailable(ctx, c, key)
+	if err == nil {
+		t.Fatal("expected a timeout")
+	}
+	want := "timed out waiting for cluster default/example to be available, last conditions: Available=False (Reconciling)"
+	if err.Error() != want {
+		t.Errorf("expected %q, got %q", want, err)
+	}
+}
+
+// TestWaitTimeoutNotFound checks that a timeout wraps the error of reading
+// a Cluster that does not exist.
+func TestWaitTimeoutNotFound(t *testing.T) {
+	c := fake.NewFakeClientWithScheme(newScheme(t))
+
+	ctx, done := shortTimeout()
+	defer done()
+	_, err := WaitForUpgrade(ctx, c, key)
+	if err == nil {
+		t.Fatal("expected a timeout")
+	}
+	if !strings.HasPrefix(err.Error(), "timed out waiting for cluster default/example to be upgraded: ") {
+		t.Errorf("unexpected error %q", err)
+	}
+	var status *apierrors.StatusError
+	if !errors.As(err, &status) || !apierrors.IsNotFound(status) {
+		t.Errorf("expected the error to wrap a NotFound error, got %v", err)
+	}
+}
@@ -0,0 +1,38 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import "testing"
+
+// TestControllerOptionsRateLimiter checks that the rate limiters of two
+// controllers configured alike do not share the state of their items.
+func TestControllerOptionsRateLimiter(t *testing.T) {
+	cfg := Default()
+	first := cfg.ControllerOptions(cfg.Controllers.Cluster).RateLimiter
+	second := cfg.ControllerOptions(cfg.Controllers.Cluster).RateLimiter
+
+	first.When("ns/name")
+	first.When("ns/name")
+	if got := second.NumRequeues("ns/name"); got != 0 {
+		t.Errorf("expected no requeue in the second rate limiter, got %d", got)
+	}
+	second.When("ns/name")
+	second.Forget("ns/name")
+	if got := first.NumRequeues("ns/name"); got != 2 {
+		t.Errorf("expected 2 requeues in the first rate limiter, got %d", got)
+	}
+}
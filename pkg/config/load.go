@@ -0,0 +1,295 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+
+	"github.com/darkowlzz/hco/pkg/alerts"
+	"github.com/darkowlzz/hco/pkg/tracing"
+)
+
+// BindServerFlags binds the flags of the settings shared by all the servers
+// of the manager binary: the metrics, health probe and webhook servers, and
+// the watched namespaces.
+func (c *ManagerConfiguration) BindServerFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.Metrics.BindAddress, "metrics-addr", c.Metrics.BindAddress,
+		"The address the metric endpoint binds to.")
+	fs.StringVar(&c.Health.HealthProbeBindAddress, "health-probe-addr", c.Health.HealthProbeBindAddress,
+		"The address the health probe endpoints bind to.")
+	fs.IntVar(&c.Webhook.Port, "webhook-port", c.Webhook.Port,
+		"The port the webhook server listens on.")
+	fs.StringVar(&c.Webhook.CertDir, "webhook-cert-dir", c.Webhook.CertDir,
+		"The directory containing the webhook server key and certificate.")
+	fs.Var((*stringList)(&c.WatchNamespaces), "watch-namespace",
+		"Comma-separated list of namespaces to watch. All namespaces are watched when empty. "+
+			"Defaults to the value of the WATCH_NAMESPACE environment variable.")
+}
+
+// BindFlags binds the command line flags of the manager to the fields of
+// the configuration, so that a flag set on the command line overrides the
+// value read from the configuration file.
+func (c *ManagerConfiguration) BindFlags(fs *flag.FlagSet) {
+	c.BindServerFlags(fs)
+	fs.BoolVar(&c.LeaderElection.LeaderElect, "enable-leader-election", c.LeaderElection.LeaderElect,
+		"Enable leader election for controller manager. "+
+			"Enabling this will ensure there is only one active controller manager.")
+	fs.StringVar(&c.LeaderElection.ResourceName, "leader-election-id", c.LeaderElection.ResourceName,
+		"The name of the leader election lock.")
+	fs.StringVar(&c.LeaderElection.ResourceNamespace, "leader-election-namespace", c.LeaderElection.ResourceNamespace,
+		"The namespace of the leader election lock. "+
+			"Defaults to the value of the POD_NAMESPACE environment variable, or to the namespace of the pod.")
+	fs.DurationVar(&c.LeaderElection.LeaseDuration.Duration, "leader-election-lease-duration", c.LeaderElection.LeaseDuration.Duration,
+		"The time the replicas that do not hold the lease wait before trying to acquire it.")
+	fs.DurationVar(&c.LeaderElection.RenewDeadline.Duration, "leader-election-renew-deadline", c.LeaderElection.RenewDeadline.Duration,
+		"The time the replica holding the lease retries to renew it before giving it up.")
+	fs.DurationVar(&c.LeaderElection.RetryPeriod.Duration, "leader-election-retry-period", c.LeaderElection.RetryPeriod.Duration,
+		"The time the replicas wait between the attempts to acquire or renew the lease.")
+	fs.BoolVar(&c.Webhook.Enabled, "enable-webhooks", c.Webhook.Enabled,
+		"Enable the admission webhooks. Requires the webhook serving certificates. "+
+			"Defaults to the value of the ENABLE_WEBHOOKS environment variable.")
+	fs.IntVar(&c.MaxConcurrentReconciles, "max-concurrent-reconciles", c.MaxConcurrentReconciles,
+		"The maximum number of concurrent reconciles of the controllers that do not set their own.")
+	for name, cc := range c.Controllers.byName() {
+		cc.bindFlags(fs, name)
+	}
+	fs.DurationVar(&c.SyncPeriod.Duration, "sync-period", c.SyncPeriod.Duration,
+		"The minimum interval at which the watched objects are reconciled.")
+	fs.BoolVar(&c.Alerts.Enabled, "enable-alerts", c.Alerts.Enabled,
+		"Maintain a PrometheusRule with the default alerts for the Clusters.")
+	fs.StringVar(&c.Alerts.Namespace, "alerts-namespace", c.Alerts.Namespace,
+		"The namespace of the PrometheusRule. Defaults to the value of the POD_NAMESPACE environment variable.")
+	fs.Var((*stringList)(&c.Alerts.Disabled), "disabled-alerts",
+		"Comma-separated list of the alerts to leave out of the PrometheusRule.")
+	fs.DurationVar(&c.Alerts.DegradedFor.Duration, "alert-degraded-for", c.Alerts.DegradedFor.Duration,
+		"The time a Cluster has to be degraded before alerting.")
+	fs.DurationVar(&c.Alerts.UpgradeStuckFor.Duration, "alert-upgrade-stuck-for", c.Alerts.UpgradeStuckFor.Duration,
+		"The time a Cluster has to be upgrading before alerting.")
+	fs.DurationVar(&c.Alerts.ComponentNotReadyFor.Duration, "alert-component-not-ready-for", c.Alerts.ComponentNotReadyFor.Duration,
+		"The time a Cluster component has to be not ready before alerting.")
+	fs.DurationVar(&c.Alerts.CrashLoopingFor.Duration, "alert-crash-looping-for", c.Alerts.CrashLoopingFor.Duration,
+		"The time the containers of a Cluster have to keep restarting before alerting.")
+	fs.StringVar(&c.Tracing.Exporter, "tracing-exporter", c.Tracing.Exporter,
+		"The exporter of the reconcile spans, stdout or otlp. Tracing is disabled when empty.")
+	fs.StringVar(&c.Tracing.Endpoint, "tracing-endpoint", c.Tracing.Endpoint,
+		"The address of the OTLP collector.")
+	fs.BoolVar(&c.Tracing.Insecure, "tracing-insecure", c.Tracing.Insecure,
+		"Disable the transport security of the connection to the OTLP collector.")
+	fs.Float64Var(&c.Tracing.SamplingRatio, "tracing-sampling-ratio", c.Tracing.SamplingRatio,
+		"The ratio of the reconciles that are traced, between 0 and 1.")
+}
+
+// Load reads the configuration file at path on top of c. Fields missing from
+// the file keep their current value. The result is not validated, call
+// Validate once the command line flags have been applied.
+func (c *ManagerConfiguration) Load(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read the configuration file: %w", err)
+	}
+	// The defaults set the version and kind, check the ones of the file.
+	var typeMeta metav1.TypeMeta
+	if err := yaml.Unmarshal(data, &typeMeta); err != nil {
+		return fmt.Errorf("failed to decode the configuration file %s: %w", path, err)
+	}
+	if typeMeta.APIVersion != APIVersion || typeMeta.Kind != Kind {
+		return fmt.Errorf("the configuration file %s must have apiVersion %s and kind %s, got apiVersion %q and kind %q",
+			path, APIVersion, Kind, typeMeta.APIVersion, typeMeta.Kind)
+	}
+	if err := yaml.UnmarshalStrict(data, c); err != nil {
+		return fmt.Errorf("failed to decode the configuration file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Validate validates the configuration.
+func (c *ManagerConfiguration) Validate() field.ErrorList {
+	var errs field.ErrorList
+	if c.APIVersion != APIVersion {
+		errs = append(errs, field.NotSupported(field.NewPath("apiVersion"), c.APIVersion, []string{APIVersion}))
+	}
+	if c.Kind != Kind {
+		errs = append(errs, field.NotSupported(field.NewPath("kind"), c.Kind, []string{Kind}))
+	}
+	if c.Webhook.Port <= 0 || c.Webhook.Port > 65535 {
+		errs = append(errs, field.Invalid(field.NewPath("webhook", "port"), c.Webhook.Port, "must be a valid port number"))
+	}
+	if c.Webhook.Enabled && c.Webhook.CertDir == "" {
+		errs = append(errs, field.Required(field.NewPath("webhook", "certDir"), "required when the webhooks are enabled"))
+	}
+	if c.LeaderElection.LeaderElect {
+		errs = append(errs, c.LeaderElection.validate(field.NewPath("leaderElection"))...)
+	}
+	if c.MaxConcurrentReconciles < 1 {
+		errs = append(errs, field.Invalid(field.NewPath("maxConcurrentReconciles"), c.MaxConcurrentReconciles, "must be at least 1"))
+	}
+	for name, cc := range c.Controllers.byName() {
+		errs = append(errs, cc.validate(field.NewPath("controllers", name))...)
+	}
+	if c.SyncPeriod.Duration <= 0 {
+		errs = append(errs, field.Invalid(field.NewPath("syncPeriod"), c.SyncPeriod.Duration.String(), "must be positive"))
+	}
+	alertsPath := field.NewPath("alerts")
+	for name, d := range map[string]int64{
+		"degradedFor":          int64(c.Alerts.DegradedFor.Duration),
+		"upgradeStuckFor":      int64(c.Alerts.UpgradeStuckFor.Duration),
+		"componentNotReadyFor": int64(c.Alerts.ComponentNotReadyFor.Duration),
+		"crashLoopingFor":      int64(c.Alerts.CrashLoopingFor.Duration),
+	} {
+		if d < 0 {
+			errs = append(errs, field.Invalid(alertsPath.Child(name), d, "must not be negative"))
+		}
+	}
+	known := map[string]bool{}
+	for _, name := range alerts.Names {
+		known[name] = true
+	}
+	for i, name := range c.Alerts.Disabled {
+		if !known[name] {
+			errs = append(errs, field.NotSupported(alertsPath.Child("disabled").Index(i), name, alerts.Names))
+		}
+	}
+	errs = append(errs, c.Tracing.validate(field.NewPath("tracing"))...)
+	return errs
+}
+
+func (c *TracingConfiguration) validate(path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	switch c.Exporter {
+	case tracing.ExporterNone, tracing.ExporterStdout:
+	case tracing.ExporterOTLP:
+		if c.Endpoint == "" {
+			errs = append(errs, field.Required(path.Child("endpoint"), "required by the otlp exporter"))
+		}
+	default:
+		errs = append(errs, field.NotSupported(path.Child("exporter"), c.Exporter,
+			[]string{tracing.ExporterStdout, tracing.ExporterOTLP}))
+	}
+	if c.SamplingRatio < 0 || c.SamplingRatio > 1 {
+		errs = append(errs, field.Invalid(path.Child("samplingRatio"), c.SamplingRatio, "must be between 0 and 1"))
+	}
+	return errs
+}
+
+func (c *LeaderElectionConfiguration) validate(path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if c.ResourceName == "" {
+		errs = append(errs, field.Required(path.Child("resourceName"), "required when leader election is enabled"))
+	}
+	// The lock is an object named after the resource name. With an invalid
+	// name, the lock is never created and no replica becomes the leader.
+	for _, msg := range validation.IsDNS1123Subdomain(c.ResourceName) {
+		errs = append(errs, field.Invalid(path.Child("resourceName"), c.ResourceName, msg))
+	}
+	if c.RetryPeriod.Duration <= 0 {
+		errs = append(errs, field.Invalid(path.Child("retryPeriod"), c.RetryPeriod.Duration.String(), "must be positive"))
+	}
+	// The lease holder retries to renew the lease with a jitter of up to
+	// 20% of the retry period.
+	if c.RenewDeadline.Duration <= c.RetryPeriod.Duration*6/5 {
+		errs = append(errs, field.Invalid(path.Child("renewDeadline"), c.RenewDeadline.Duration.String(),
+			"must be greater than 1.2 times retryPeriod"))
+	}
+	if c.LeaseDuration.Duration <= c.RenewDeadline.Duration {
+		errs = append(errs, field.Invalid(path.Child("leaseDuration"), c.LeaseDuration.Duration.String(),
+			"must be greater than renewDeadline"))
+	}
+	return errs
+}
+
+// byName returns the configuration of each controller by the name used in
+// the configuration file.
+func (c *ControllersConfiguration) byName() map[string]*ControllerConfiguration {
+	return map[string]*ControllerConfiguration{
+		"cluster":  &c.Cluster,
+		"app":      &c.App,
+		"sidecarA": &c.SidecarA,
+		"sidecarB": &c.SidecarB,
+	}
+}
+
+// bindFlags binds the flags of a controller, prefixed with the lowercased
+// name of the controller.
+func (c *ControllerConfiguration) bindFlags(fs *flag.FlagSet, name string) {
+	prefix := strings.ToLower(name) + "-"
+	fs.IntVar(&c.MaxConcurrentReconciles, prefix+"max-concurrent-reconciles", c.MaxConcurrentReconciles,
+		fmt.Sprintf("The maximum number of concurrent reconciles of the %s controller. "+
+			"Defaults to --max-concurrent-reconciles.", name))
+	fs.DurationVar(&c.RateLimiter.BaseDelay.Duration, prefix+"rate-limiter-base-delay", c.RateLimiter.BaseDelay.Duration,
+		fmt.Sprintf("The delay of the first retry of a failed object in the %s controller.", name))
+	fs.DurationVar(&c.RateLimiter.MaxDelay.Duration, prefix+"rate-limiter-max-delay", c.RateLimiter.MaxDelay.Duration,
+		fmt.Sprintf("The maximum delay of the retries of a failed object in the %s controller.", name))
+	fs.Float64Var(&c.RateLimiter.QPS, prefix+"rate-limiter-qps", c.RateLimiter.QPS,
+		fmt.Sprintf("The overall rate of requests of the %s controller workqueue.", name))
+	fs.IntVar(&c.RateLimiter.Burst, prefix+"rate-limiter-burst", c.RateLimiter.Burst,
+		fmt.Sprintf("The overall burst of requests of the %s controller workqueue.", name))
+}
+
+func (c *ControllerConfiguration) validate(path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if c.MaxConcurrentReconciles < 0 {
+		errs = append(errs, field.Invalid(path.Child("maxConcurrentReconciles"), c.MaxConcurrentReconciles, "must not be negative"))
+	}
+	rl := path.Child("rateLimiter")
+	if c.RateLimiter.BaseDelay.Duration <= 0 {
+		errs = append(errs, field.Invalid(rl.Child("baseDelay"), c.RateLimiter.BaseDelay.Duration.String(), "must be positive"))
+	}
+	if c.RateLimiter.MaxDelay.Duration < c.RateLimiter.BaseDelay.Duration {
+		errs = append(errs, field.Invalid(rl.Child("maxDelay"), c.RateLimiter.MaxDelay.Duration.String(), "must not be less than baseDelay"))
+	}
+	if c.RateLimiter.QPS <= 0 {
+		errs = append(errs, field.Invalid(rl.Child("qps"), c.RateLimiter.QPS, "must be positive"))
+	}
+	if c.RateLimiter.Burst < 1 {
+		errs = append(errs, field.Invalid(rl.Child("burst"), c.RateLimiter.Burst, "must be at least 1"))
+	}
+	return errs
+}
+
+// stringList is a flag.Value for a comma-separated list.
+type stringList []string
+
+func (l *stringList) String() string {
+	if l == nil {
+		return ""
+	}
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = splitList(value)
+	return nil
+}
+
+// splitList splits a comma-separated list, dropping empty entries.
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
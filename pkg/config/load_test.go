@@ -0,0 +1,104 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLoadTypeMeta checks that a configuration file must set its version
+// and kind.
+func TestLoadTypeMeta(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for name, test := range map[string]struct {
+		data string
+		err  string
+	}{
+		"complete": {
+			data: "apiVersion: " + APIVersion + "\nkind: " + Kind + "\nmetrics:\n  bindAddress: :9090\n",
+		},
+		"missing": {
+			data: "metrics:\n  bindAddress: :9090\n",
+			err:  "must have apiVersion",
+		},
+		"other kind": {
+			data: "apiVersion: " + APIVersion + "\nkind: Other\n",
+			err:  `got apiVersion "` + APIVersion + `" and kind "Other"`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, "config.yaml")
+			if err := ioutil.WriteFile(path, []byte(test.data), 0644); err != nil {
+				t.Fatal(err)
+			}
+			cfg := Default()
+			err := cfg.Load(path)
+			if test.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if cfg.Metrics.BindAddress != ":9090" {
+					t.Errorf("expected the file to be loaded, got metrics address %q", cfg.Metrics.BindAddress)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("expected an error containing %q, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+// TestValidateLeaderElectionResourceName checks that a lock name that cannot
+// name an object is rejected.
+func TestValidateLeaderElectionResourceName(t *testing.T) {
+	for name, valid := range map[string]bool{
+		"hco.darkowlzz.space": true,
+		"25a4e4dd.":           false,
+		"Hco":                 false,
+	} {
+		cfg := Default()
+		cfg.LeaderElection.LeaderElect = true
+		cfg.LeaderElection.ResourceName = name
+		errs := cfg.Validate()
+		if valid && len(errs) > 0 {
+			t.Errorf("expected %q to be valid, got %v", name, errs.ToAggregate())
+		}
+		if !valid && len(errs) == 0 {
+			t.Errorf("expected %q to be invalid", name)
+		}
+	}
+}
+
+// TestValidateDisabledAlerts checks that the disabled alerts must be known.
+func TestValidateDisabledAlerts(t *testing.T) {
+	cfg := Default()
+	cfg.Alerts.Disabled = []string{"HcoClusterDegraded", "HcoClusterDegradd"}
+	errs := cfg.Validate()
+	if len(errs) != 1 || errs[0].Field != "alerts.disabled[1]" {
+		t.Errorf("expected an error on alerts.disabled[1], got %v", errs.ToAggregate())
+	}
+}
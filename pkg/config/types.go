@@ -0,0 +1,278 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package config contains the versioned configuration file of the manager.
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// APIVersion is the API version of the manager configuration.
+	APIVersion = "config.darkowlzz.space/v1alpha1"
+
+	// Kind is the kind of the manager configuration.
+	Kind = "ManagerConfiguration"
+)
+
+// ManagerConfiguration is the configuration of the manager.
+type ManagerConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Metrics configures the metrics server.
+	Metrics MetricsConfiguration `json:"metrics,omitempty"`
+
+	// Health configures the health probe server.
+	Health HealthConfiguration `json:"health,omitempty"`
+
+	// Webhook configures the admission webhook server.
+	Webhook WebhookConfiguration `json:"webhook,omitempty"`
+
+	// LeaderElection configures the leader election of the manager.
+	LeaderElection LeaderElectionConfiguration `json:"leaderElection,omitempty"`
+
+	// WatchNamespaces is the list of namespaces to watch. All namespaces are
+	// watched when empty.
+	WatchNamespaces []string `json:"watchNamespaces,omitempty"`
+
+	// MaxConcurrentReconciles is the maximum number of concurrent reconciles
+	// of the controllers that do not set their own.
+	MaxConcurrentReconciles int `json:"maxConcurrentReconciles,omitempty"`
+
+	// Controllers configures each controller of the manager.
+	Controllers ControllersConfiguration `json:"controllers,omitempty"`
+
+	// SyncPeriod is the minimum interval at which the watched objects are
+	// reconciled.
+	SyncPeriod metav1.Duration `json:"syncPeriod,omitempty"`
+
+	// Alerts configures the PrometheusRule maintained by the operator.
+	Alerts AlertsConfiguration `json:"alerts,omitempty"`
+
+	// Tracing configures the tracing of the reconciles.
+	Tracing TracingConfiguration `json:"tracing,omitempty"`
+}
+
+// MetricsConfiguration configures the metrics server.
+type MetricsConfiguration struct {
+	// BindAddress is the address the metrics endpoint binds to. Set it to
+	// "0" to disable the metrics server.
+	BindAddress string `json:"bindAddress,omitempty"`
+}
+
+// HealthConfiguration configures the health probe server.
+type HealthConfiguration struct {
+	// HealthProbeBindAddress is the address the health probe endpoints bind
+	// to. Set it to "0" to disable the health probe server.
+	HealthProbeBindAddress string `json:"healthProbeBindAddress,omitempty"`
+}
+
+// WebhookConfiguration configures the admission webhook server.
+type WebhookConfiguration struct {
+	// Enabled enables the admission webhooks.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Port is the port the webhook server listens on.
+	Port int `json:"port,omitempty"`
+
+	// CertDir is the directory containing the webhook server key and
+	// certificate.
+	CertDir string `json:"certDir,omitempty"`
+}
+
+// LeaderElectionConfiguration configures the leader election of the
+// manager.
+type LeaderElectionConfiguration struct {
+	// LeaderElect enables leader election, ensuring that only one manager
+	// is active at a time.
+	LeaderElect bool `json:"leaderElect,omitempty"`
+
+	// ResourceName is the name of the leader election lock. Replicas only
+	// exclude each other when they use the same name, so changing it while
+	// replicas are running lets a replica with the old name and a replica
+	// with the new name lead at the same time. Scale the manager down to
+	// zero replicas before rolling out a new name.
+	ResourceName string `json:"resourceName,omitempty"`
+
+	// ResourceNamespace is the namespace of the leader election lock.
+	// Defaults to the POD_NAMESPACE environment variable, or to the
+	// namespace of the pod when it is not set.
+	ResourceNamespace string `json:"resourceNamespace,omitempty"`
+
+	// LeaseDuration is the time the replicas that do not hold the lease wait
+	// before trying to acquire it.
+	LeaseDuration metav1.Duration `json:"leaseDuration,omitempty"`
+
+	// RenewDeadline is the time the replica holding the lease retries to
+	// renew it before giving it up.
+	RenewDeadline metav1.Duration `json:"renewDeadline,omitempty"`
+
+	// RetryPeriod is the time the replicas wait between the attempts to
+	// acquire or renew the lease.
+	RetryPeriod metav1.Duration `json:"retryPeriod,omitempty"`
+}
+
+// ControllersConfiguration configures each controller of the manager.
+type ControllersConfiguration struct {
+	Cluster  ControllerConfiguration `json:"cluster,omitempty"`
+	App      ControllerConfiguration `json:"app,omitempty"`
+	SidecarA ControllerConfiguration `json:"sidecarA,omitempty"`
+	SidecarB ControllerConfiguration `json:"sidecarB,omitempty"`
+}
+
+// ControllerConfiguration configures a controller.
+type ControllerConfiguration struct {
+	// MaxConcurrentReconciles is the maximum number of concurrent reconciles
+	// of the controller. Defaults to the MaxConcurrentReconciles of the
+	// manager configuration.
+	MaxConcurrentReconciles int `json:"maxConcurrentReconciles,omitempty"`
+
+	// RateLimiter configures the rate limiter of the controller workqueue.
+	RateLimiter RateLimiterConfiguration `json:"rateLimiter,omitempty"`
+}
+
+// RateLimiterConfiguration configures the rate limiter of a controller
+// workqueue. A request is delayed by the larger of a per-object exponential
+// backoff and an overall token bucket.
+type RateLimiterConfiguration struct {
+	// BaseDelay is the delay of the first retry of a failed object. It is
+	// doubled on every consecutive failure.
+	BaseDelay metav1.Duration `json:"baseDelay,omitempty"`
+
+	// MaxDelay is the maximum delay of the retries of a failed object.
+	MaxDelay metav1.Duration `json:"maxDelay,omitempty"`
+
+	// QPS is the overall rate of requests of the workqueue.
+	QPS float64 `json:"qps,omitempty"`
+
+	// Burst is the overall burst of requests of the workqueue.
+	Burst int `json:"burst,omitempty"`
+}
+
+// AlertsConfiguration configures the PrometheusRule maintained by the
+// operator.
+type AlertsConfiguration struct {
+	// Enabled controls whether the PrometheusRule is maintained.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Namespace is the namespace of the PrometheusRule.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Disabled is the list of the alerts to leave out of the PrometheusRule.
+	Disabled []string `json:"disabled,omitempty"`
+
+	// DegradedFor is the time a Cluster has to be degraded before alerting.
+	DegradedFor metav1.Duration `json:"degradedFor,omitempty"`
+
+	// UpgradeStuckFor is the time a Cluster has to be upgrading before
+	// alerting.
+	UpgradeStuckFor metav1.Duration `json:"upgradeStuckFor,omitempty"`
+
+	// ComponentNotReadyFor is the time a component has to be not ready
+	// before alerting.
+	ComponentNotReadyFor metav1.Duration `json:"componentNotReadyFor,omitempty"`
+
+	// CrashLoopingFor is the time the containers of a Cluster have to keep
+	// restarting before alerting.
+	CrashLoopingFor metav1.Duration `json:"crashLoopingFor,omitempty"`
+}
+
+// TracingConfiguration configures the tracing of the reconciles.
+type TracingConfiguration struct {
+	// Exporter is the exporter of the spans: "stdout" or "otlp". Tracing is
+	// disabled when empty.
+	Exporter string `json:"exporter,omitempty"`
+
+	// Endpoint is the address of the OTLP collector.
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Insecure disables the transport security of the connection to the
+	// OTLP collector.
+	Insecure bool `json:"insecure,omitempty"`
+
+	// SamplingRatio is the ratio of the reconciles that are traced, between
+	// 0 and 1.
+	SamplingRatio float64 `json:"samplingRatio,omitempty"`
+}
+
+// Default returns the default manager configuration. Some defaults are read
+// from the environment: ENABLE_WEBHOOKS, WATCH_NAMESPACE and POD_NAMESPACE.
+// The lease timings are the defaults of controller-runtime.
+func Default() *ManagerConfiguration {
+	return &ManagerConfiguration{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: APIVersion,
+			Kind:       Kind,
+		},
+		Metrics: MetricsConfiguration{
+			BindAddress: ":8080",
+		},
+		Health: HealthConfiguration{
+			HealthProbeBindAddress: ":8081",
+		},
+		Webhook: WebhookConfiguration{
+			Enabled: os.Getenv("ENABLE_WEBHOOKS") == "true",
+			Port:    9443,
+			CertDir: filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs"),
+		},
+		LeaderElection: LeaderElectionConfiguration{
+			ResourceName:      "hco.darkowlzz.space",
+			ResourceNamespace: os.Getenv("POD_NAMESPACE"),
+			LeaseDuration:     metav1.Duration{Duration: 15 * time.Second},
+			RenewDeadline:     metav1.Duration{Duration: 10 * time.Second},
+			RetryPeriod:       metav1.Duration{Duration: 2 * time.Second},
+		},
+		WatchNamespaces:         splitList(os.Getenv("WATCH_NAMESPACE")),
+		MaxConcurrentReconciles: 1,
+		Controllers: ControllersConfiguration{
+			Cluster:  defaultControllerConfiguration(),
+			App:      defaultControllerConfiguration(),
+			SidecarA: defaultControllerConfiguration(),
+			SidecarB: defaultControllerConfiguration(),
+		},
+		SyncPeriod: metav1.Duration{Duration: 10 * time.Hour},
+		Alerts: AlertsConfiguration{
+			Enabled:              true,
+			Namespace:            os.Getenv("POD_NAMESPACE"),
+			DegradedFor:          metav1.Duration{Duration: 10 * time.Minute},
+			UpgradeStuckFor:      metav1.Duration{Duration: 30 * time.Minute},
+			ComponentNotReadyFor: metav1.Duration{Duration: 15 * time.Minute},
+			CrashLoopingFor:      metav1.Duration{Duration: 15 * time.Minute},
+		},
+		Tracing: TracingConfiguration{
+			SamplingRatio: 1,
+		},
+	}
+}
+
+// defaultControllerConfiguration returns the default configuration of a
+// controller. The rate limiter matches the default controller rate limiter
+// of client-go.
+func defaultControllerConfiguration() ControllerConfiguration {
+	return ControllerConfiguration{
+		RateLimiter: RateLimiterConfiguration{
+			BaseDelay: metav1.Duration{Duration: 5 * time.Millisecond},
+			MaxDelay:  metav1.Duration{Duration: 1000 * time.Second},
+			QPS:       10,
+			Burst:     100,
+		},
+	}
+}
@@ -0,0 +1,215 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package health provides the readiness checks of the manager.
+package health
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// cacheSyncTimeout bounds the time a readiness probe waits on the cache.
+const cacheSyncTimeout = time.Second
+
+// inClusterNamespacePath is the file containing the namespace of the pod.
+const inClusterNamespacePath = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// leaderGauge reports whether a replica holds the leader lease.
+var leaderGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "hco_leader_election_is_leader",
+	Help: "Whether the replica holds the leader lease (1) or not (0).",
+}, []string{"replica"})
+
+func init() {
+	metrics.Registry.MustRegister(leaderGauge)
+}
+
+// CacheSyncCheck returns a checker that succeeds once the informers of the
+// cache have started and synced.
+func CacheSyncCheck(c cache.Cache) healthz.Checker {
+	return func(_ *http.Request) error {
+		stop := make(chan struct{})
+		timer := time.AfterFunc(cacheSyncTimeout, func() { close(stop) })
+		defer timer.Stop()
+		if !c.WaitForCacheSync(stop) {
+			return errors.New("caches not synced")
+		}
+		return nil
+	}
+}
+
+// WebhookCertCheck returns a checker that succeeds when certDir contains a
+// valid serving certificate and key for the webhook server.
+func WebhookCertCheck(certDir string) healthz.Checker {
+	return func(_ *http.Request) error {
+		pair, err := tls.LoadX509KeyPair(filepath.Join(certDir, "tls.crt"), filepath.Join(certDir, "tls.key"))
+		if err != nil {
+			return fmt.Errorf("failed to load webhook certificate: %w", err)
+		}
+		cert, err := x509.ParseCertificate(pair.Certificate[0])
+		if err != nil {
+			return fmt.Errorf("failed to parse webhook certificate: %w", err)
+		}
+		now := time.Now()
+		if now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+			return fmt.Errorf("webhook certificate is not valid at %s, valid from %s to %s",
+				now.Format(time.RFC3339), cert.NotBefore.Format(time.RFC3339), cert.NotAfter.Format(time.RFC3339))
+		}
+		return nil
+	}
+}
+
+// LeaderElection tracks the outcome of the manager leader election. It is
+// added to the manager as a Runnable, which the manager only starts once this
+// replica has been elected.
+type LeaderElection struct {
+	reader    client.Reader
+	log       logr.Logger
+	namespace string
+	id        string
+	replica   string
+	elected   int32
+}
+
+// NewLeaderElection returns a LeaderElection for the lock with the given
+// namespace and ID. The namespace of the pod is used when namespace is
+// empty, like the manager does. The replica is identified by its host name,
+// which is the name of the pod and the prefix of its lease holder identity.
+func NewLeaderElection(reader client.Reader, log logr.Logger, namespace, id string) *LeaderElection {
+	replica, _ := os.Hostname()
+	leaderGauge.WithLabelValues(replica).Set(0)
+	return &LeaderElection{reader: reader, log: log, namespace: namespace, id: id, replica: replica}
+}
+
+// Start implements manager.Runnable. It records that this replica holds the
+// lease.
+func (l *LeaderElection) Start(stop <-chan struct{}) error {
+	atomic.StoreInt32(&l.elected, 1)
+	leaderGauge.WithLabelValues(l.replica).Set(1)
+	l.log.Info("acquired the leader lease", "replica", l.replica, "lock", l.namespace+"/"+l.id)
+	<-stop
+	return nil
+}
+
+// Monitor returns a Runnable that logs the identity of the lease holder each
+// time it changes. Unlike the LeaderElection itself it runs on every replica.
+func (l *LeaderElection) Monitor(interval time.Duration) manager.Runnable {
+	return &leaseMonitor{election: l, interval: interval}
+}
+
+// Elected returns true when this replica holds the lease.
+func (l *LeaderElection) Elected() bool {
+	return atomic.LoadInt32(&l.elected) == 1
+}
+
+// Holder returns the identity of the current lease holder, or an empty string
+// when the lease is not held.
+func (l *LeaderElection) Holder(ctx context.Context) (string, error) {
+	namespace := l.namespace
+	if namespace == "" {
+		data, err := ioutil.ReadFile(inClusterNamespacePath)
+		if err != nil {
+			return "", fmt.Errorf("failed to find the leader election namespace: %w", err)
+		}
+		namespace = strings.TrimSpace(string(data))
+	}
+
+	var cm corev1.ConfigMap
+	if err := l.reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: l.id}, &cm); err != nil {
+		return "", client.IgnoreNotFound(err)
+	}
+	data, ok := cm.Annotations[resourcelock.LeaderElectionRecordAnnotationKey]
+	if !ok {
+		return "", nil
+	}
+	var record resourcelock.LeaderElectionRecord
+	if err := json.Unmarshal([]byte(data), &record); err != nil {
+		return "", fmt.Errorf("failed to decode the leader election record: %w", err)
+	}
+	expiry := record.RenewTime.Add(time.Duration(record.LeaseDurationSeconds) * time.Second)
+	if time.Now().After(expiry) {
+		return "", nil
+	}
+	return record.HolderIdentity, nil
+}
+
+// Check is a healthz.Checker that succeeds once the leader election is
+// resolved, that is when this replica or another one holds the lease.
+func (l *LeaderElection) Check(req *http.Request) error {
+	if l.Elected() {
+		return nil
+	}
+	holder, err := l.Holder(req.Context())
+	if err != nil {
+		return err
+	}
+	if holder == "" {
+		return errors.New("leader election not resolved")
+	}
+	return nil
+}
+
+// leaseMonitor logs the changes of the lease holder.
+type leaseMonitor struct {
+	election *LeaderElection
+	interval time.Duration
+	holder   string
+}
+
+// Start implements manager.Runnable.
+func (m *leaseMonitor) Start(stop <-chan struct{}) error {
+	wait.Until(func() {
+		holder, err := m.election.Holder(context.Background())
+		if err != nil {
+			m.election.log.Info("failed to get the leader lease holder", "error", err)
+			return
+		}
+		if holder != m.holder {
+			m.election.log.Info("leader lease holder changed", "holder", holder, "replica", m.election.replica)
+			m.holder = holder
+		}
+	}, m.interval, stop)
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (m *leaseMonitor) NeedLeaderElection() bool {
+	return false
+}
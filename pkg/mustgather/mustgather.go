@@ -0,0 +1,592 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package mustgather collects the diagnostics of the Clusters of a namespace
+// into a tarball: the Clusters, the objects related to them, their events,
+// the logs of their pods and of the operator, and the RBAC bound to the
+// service accounts of these pods.
+package mustgather
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	darkowlzzspacev1 "github.com/darkowlzz/hco/api/v1"
+)
+
+// RedactedValue replaces the values of the Secrets in the tarball.
+const RedactedValue = "REDACTED"
+
+// lastAppliedAnnotation holds the last applied configuration of an object,
+// which includes the data of a Secret applied with kubectl.
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// ownedKinds are the kinds searched for objects owned, directly or not, by
+// the Clusters.
+var ownedKinds = []schema.GroupVersionKind{
+	darkowlzzspacev1.GroupVersion.WithKind("App"),
+	darkowlzzspacev1.GroupVersion.WithKind("SidecarA"),
+	darkowlzzspacev1.GroupVersion.WithKind("SidecarB"),
+	{Group: "apps", Version: "v1", Kind: "Deployment"},
+	{Group: "apps", Version: "v1", Kind: "ReplicaSet"},
+	{Group: "apps", Version: "v1", Kind: "StatefulSet"},
+	{Group: "apps", Version: "v1", Kind: "DaemonSet"},
+	{Group: "", Version: "v1", Kind: "Pod"},
+	{Group: "", Version: "v1", Kind: "Service"},
+	{Group: "", Version: "v1", Kind: "Endpoints"},
+	{Group: "", Version: "v1", Kind: "ConfigMap"},
+	{Group: "", Version: "v1", Kind: "Secret"},
+	{Group: "", Version: "v1", Kind: "PersistentVolumeClaim"},
+}
+
+var (
+	clusterGVK            = darkowlzzspacev1.GroupVersion.WithKind("Cluster")
+	podGVK                = schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Pod"}
+	secretGVK             = schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Secret"}
+	eventGVK              = schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Event"}
+	serviceAccountGVK     = schema.GroupVersionKind{Group: "", Version: "v1", Kind: "ServiceAccount"}
+	roleBindingGVK        = schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "RoleBinding"}
+	clusterRoleBindingGVK = schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRoleBinding"}
+)
+
+// Options configures a Collector.
+type Options struct {
+	// Namespace is the namespace of the Clusters to collect.
+	Namespace string
+
+	// ClusterName restricts the collection to a single Cluster. All the
+	// Clusters of the namespace are collected when empty.
+	ClusterName string
+
+	// OperatorNamespace is the namespace the operator runs in. The operator
+	// is not collected when empty.
+	OperatorNamespace string
+
+	// OperatorSelector selects the pods of the operator in
+	// OperatorNamespace.
+	OperatorSelector labels.Selector
+
+	// LogTailLines limits the number of lines collected from the log of a
+	// container. The whole log is collected when zero.
+	LogTailLines int64
+}
+
+// Collector collects the diagnostics of Clusters.
+type Collector struct {
+	// Client reads the objects.
+	Client client.Reader
+
+	// Logs reads the logs of the containers.
+	Logs LogGetter
+
+	Log logr.Logger
+
+	Options
+}
+
+// LogGetter reads the log of a container.
+type LogGetter interface {
+	GetLog(ctx context.Context, namespace, pod string, opts *corev1.PodLogOptions) (io.ReadCloser, error)
+}
+
+// PodLogs returns a LogGetter reading the logs through pods.
+func PodLogs(pods corev1client.PodsGetter) LogGetter {
+	return podLogs{pods: pods}
+}
+
+type podLogs struct {
+	pods corev1client.PodsGetter
+}
+
+func (l podLogs) GetLog(ctx context.Context, namespace, pod string, opts *corev1.PodLogOptions) (io.ReadCloser, error) {
+	return l.pods.Pods(namespace).GetLogs(pod, opts).Stream(ctx)
+}
+
+// Name returns the name of a tarball collected at t, also used as the root
+// directory of the tarball.
+func Name(t time.Time) string {
+	return "must-gather-" + t.UTC().Format("20060102T150405Z")
+}
+
+// collection is the state of a single collection.
+type collection struct {
+	*Collector
+
+	// objects are the collected objects, by UID.
+	objects map[types.UID]*unstructured.Unstructured
+
+	// errors are the failures that did not stop the collection.
+	errors []string
+}
+
+// Collect writes a gzipped tarball of the diagnostics to w, with the files
+// under the root directory. The failures to read a single object or log do
+// not stop the collection, they are listed in the errors.txt file of the
+// tarball.
+func (c *Collector) Collect(ctx context.Context, w io.Writer, root string) error {
+	col := &collection{Collector: c, objects: map[types.UID]*unstructured.Unstructured{}}
+	if err := col.collect(ctx); err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	now := time.Now()
+	var uids []string
+	for uid := range col.objects {
+		uids = append(uids, string(uid))
+	}
+	sort.Strings(uids)
+	for _, uid := range uids {
+		obj := col.objects[types.UID(uid)]
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return err
+		}
+		if err := writeFile(tw, path.Join(root, objectPath(obj)), data, now); err != nil {
+			return err
+		}
+	}
+	for _, file := range col.logs(ctx) {
+		if err := writeFile(tw, path.Join(root, file.name), file.data, now); err != nil {
+			return err
+		}
+	}
+	if len(col.errors) > 0 {
+		data := []byte(strings.Join(col.errors, "\n") + "\n")
+		if err := writeFile(tw, path.Join(root, "errors.txt"), data, now); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// collect collects the objects.
+func (c *collection) collect(ctx context.Context) error {
+	clusters, err := c.clusters(ctx)
+	if err != nil {
+		return err
+	}
+	for _, cluster := range clusters {
+		c.add(cluster)
+		c.collectRelatedObjects(ctx, cluster)
+	}
+	c.collectOwned(ctx)
+	c.collectOperator(ctx)
+	c.collectRBAC(ctx)
+	c.collectEvents(ctx)
+	return nil
+}
+
+// clusters returns the Clusters to collect.
+func (c *collection) clusters(ctx context.Context) ([]*unstructured.Unstructured, error) {
+	if c.ClusterName != "" {
+		cluster := newObject(clusterGVK)
+		key := types.NamespacedName{Namespace: c.Namespace, Name: c.ClusterName}
+		if err := c.Client.Get(ctx, key, cluster); err != nil {
+			return nil, fmt.Errorf("failed to get Cluster %s: %w", key, err)
+		}
+		return []*unstructured.Unstructured{cluster}, nil
+	}
+	list, err := c.list(ctx, clusterGVK, client.InNamespace(c.Namespace))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the Clusters of namespace %s: %w", c.Namespace, err)
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf("no Cluster found in namespace %s", c.Namespace)
+	}
+	return list, nil
+}
+
+// collectRelatedObjects collects the related objects listed in the status of
+// cluster.
+func (c *collection) collectRelatedObjects(ctx context.Context, cluster *unstructured.Unstructured) {
+	refs, _, _ := unstructured.NestedSlice(cluster.Object, "status", "relatedObjects")
+	for _, r := range refs {
+		ref, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		apiVersion, _, _ := unstructured.NestedString(ref, "apiVersion")
+		kind, _, _ := unstructured.NestedString(ref, "kind")
+		namespace, _, _ := unstructured.NestedString(ref, "namespace")
+		name, _, _ := unstructured.NestedString(ref, "name")
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(apiVersion)
+		obj.SetKind(kind)
+		key := types.NamespacedName{Namespace: namespace, Name: name}
+		if err := c.Client.Get(ctx, key, obj); err != nil {
+			if !apierrors.IsNotFound(err) {
+				c.fail(err, "failed to get related object", "kind", kind, "object", key)
+			}
+			continue
+		}
+		c.add(obj)
+	}
+}
+
+// collectOwned collects the objects of the namespace owned, directly or
+// through other owned objects, by the collected objects.
+func (c *collection) collectOwned(ctx context.Context) {
+	var candidates []*unstructured.Unstructured
+	for _, gvk := range ownedKinds {
+		list, err := c.list(ctx, gvk, client.InNamespace(c.Namespace))
+		if err != nil {
+			c.fail(err, "failed to list objects", "kind", gvk.Kind, "namespace", c.Namespace)
+			continue
+		}
+		candidates = append(candidates, list...)
+	}
+
+	// Repeat until no new object is found, an object may be owned by an
+	// object found later.
+	for found := true; found; {
+		found = false
+		for _, obj := range candidates {
+			if _, ok := c.objects[obj.GetUID()]; ok {
+				continue
+			}
+			for _, owner := range obj.GetOwnerReferences() {
+				if _, ok := c.objects[owner.UID]; ok {
+					c.add(obj)
+					found = true
+					break
+				}
+			}
+		}
+	}
+}
+
+// collectOperator collects the pods of the operator.
+func (c *collection) collectOperator(ctx context.Context) {
+	if c.OperatorNamespace == "" {
+		return
+	}
+	opts := []client.ListOption{client.InNamespace(c.OperatorNamespace)}
+	if c.OperatorSelector != nil {
+		opts = append(opts, client.MatchingLabelsSelector{Selector: c.OperatorSelector})
+	}
+	pods, err := c.list(ctx, podGVK, opts...)
+	if err != nil {
+		c.fail(err, "failed to list the operator pods", "namespace", c.OperatorNamespace)
+		return
+	}
+	if len(pods) == 0 {
+		c.fail(fmt.Errorf("no pod found"), "failed to find the operator pods", "namespace", c.OperatorNamespace)
+	}
+	for _, pod := range pods {
+		c.add(pod)
+	}
+}
+
+// collectRBAC collects the service accounts of the collected pods, the role
+// bindings and cluster role bindings with these service accounts as subject
+// and the roles they refer to.
+func (c *collection) collectRBAC(ctx context.Context) {
+	serviceAccounts := map[types.NamespacedName]bool{}
+	for _, obj := range c.objectsOfKind(podGVK) {
+		name, _, _ := unstructured.NestedString(obj.Object, "spec", "serviceAccountName")
+		if name == "" {
+			name = "default"
+		}
+		serviceAccounts[types.NamespacedName{Namespace: obj.GetNamespace(), Name: name}] = true
+	}
+
+	namespaces := map[string]bool{}
+	for key := range serviceAccounts {
+		namespaces[key.Namespace] = true
+		sa := newObject(serviceAccountGVK)
+		if err := c.Client.Get(ctx, key, sa); err != nil {
+			c.fail(err, "failed to get service account", "object", key)
+			continue
+		}
+		c.add(sa)
+	}
+
+	var bindings []*unstructured.Unstructured
+	for namespace := range namespaces {
+		list, err := c.list(ctx, roleBindingGVK, client.InNamespace(namespace))
+		if err != nil {
+			c.fail(err, "failed to list role bindings", "namespace", namespace)
+			continue
+		}
+		bindings = append(bindings, list...)
+	}
+	list, err := c.list(ctx, clusterRoleBindingGVK)
+	if err != nil {
+		c.fail(err, "failed to list cluster role bindings")
+	}
+	bindings = append(bindings, list...)
+
+	for _, binding := range bindings {
+		if !bindsServiceAccount(binding, serviceAccounts) {
+			continue
+		}
+		c.add(binding)
+
+		kind, _, _ := unstructured.NestedString(binding.Object, "roleRef", "kind")
+		name, _, _ := unstructured.NestedString(binding.Object, "roleRef", "name")
+		role := newObject(roleBindingGVK.GroupVersion().WithKind(kind))
+		key := types.NamespacedName{Name: name}
+		if kind == "Role" {
+			key.Namespace = binding.GetNamespace()
+		}
+		if err := c.Client.Get(ctx, key, role); err != nil {
+			c.fail(err, "failed to get role", "kind", kind, "object", key)
+			continue
+		}
+		c.add(role)
+	}
+}
+
+// bindsServiceAccount returns whether binding has one of serviceAccounts as
+// subject.
+func bindsServiceAccount(binding *unstructured.Unstructured, serviceAccounts map[types.NamespacedName]bool) bool {
+	subjects, _, _ := unstructured.NestedSlice(binding.Object, "subjects")
+	for _, s := range subjects {
+		subject, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		kind, _, _ := unstructured.NestedString(subject, "kind")
+		if kind != "ServiceAccount" {
+			continue
+		}
+		namespace, _, _ := unstructured.NestedString(subject, "namespace")
+		name, _, _ := unstructured.NestedString(subject, "name")
+		if namespace == "" {
+			namespace = binding.GetNamespace()
+		}
+		if serviceAccounts[types.NamespacedName{Namespace: namespace, Name: name}] {
+			return true
+		}
+	}
+	return false
+}
+
+// collectEvents collects the events of the collected objects.
+func (c *collection) collectEvents(ctx context.Context) {
+	namespaces := map[string]bool{}
+	for _, obj := range c.objects {
+		if obj.GetNamespace() != "" {
+			namespaces[obj.GetNamespace()] = true
+		}
+	}
+	for namespace := range namespaces {
+		events, err := c.list(ctx, eventGVK, client.InNamespace(namespace))
+		if err != nil {
+			c.fail(err, "failed to list events", "namespace", namespace)
+			continue
+		}
+		for _, event := range events {
+			uid, _, _ := unstructured.NestedString(event.Object, "involvedObject", "uid")
+			if _, ok := c.objects[types.UID(uid)]; ok {
+				c.add(event)
+			}
+		}
+	}
+}
+
+// file is a file of the tarball.
+type file struct {
+	name string
+	data []byte
+}
+
+// logs returns the logs of the containers of the collected pods. The logs of
+// the previous instance of a container are collected when it restarted.
+func (c *collection) logs(ctx context.Context) []file {
+	var files []file
+	pods := c.objectsOfKind(podGVK)
+	sort.Slice(pods, func(i, j int) bool { return objectPath(pods[i]) < objectPath(pods[j]) })
+	for _, obj := range pods {
+		pod := &corev1.Pod{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, pod); err != nil {
+			c.fail(err, "failed to decode pod", "object", objectKey(obj))
+			continue
+		}
+		restarts := map[string]int32{}
+		for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+			restarts[status.Name] = status.RestartCount
+		}
+		dir := path.Join("namespaces", pod.Namespace, "logs", pod.Name)
+		for _, container := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+			if data, ok := c.log(ctx, pod, container.Name, false); ok {
+				files = append(files, file{name: path.Join(dir, container.Name+".log"), data: data})
+			}
+			if restarts[container.Name] == 0 {
+				continue
+			}
+			if data, ok := c.log(ctx, pod, container.Name, true); ok {
+				files = append(files, file{name: path.Join(dir, container.Name+".previous.log"), data: data})
+			}
+		}
+	}
+	return files
+}
+
+// log returns the log of a container of pod.
+func (c *collection) log(ctx context.Context, pod *corev1.Pod, container string, previous bool) ([]byte, bool) {
+	opts := &corev1.PodLogOptions{Container: container, Previous: previous}
+	if c.LogTailLines > 0 {
+		opts.TailLines = &c.LogTailLines
+	}
+	stream, err := c.Logs.GetLog(ctx, pod.Namespace, pod.Name, opts)
+	if err != nil {
+		c.fail(err, "failed to get container log", "object", objectKey(pod), "container", container)
+		return nil, false
+	}
+	defer stream.Close()
+	data, err := ioutil.ReadAll(stream)
+	if err != nil {
+		c.fail(err, "failed to read container log", "object", objectKey(pod), "container", container)
+		return nil, false
+	}
+	return data, true
+}
+
+// add collects obj, with its Secret values redacted.
+func (c *collection) add(obj *unstructured.Unstructured) {
+	obj = obj.DeepCopy()
+	obj.SetManagedFields(nil)
+	if obj.GroupVersionKind() == secretGVK {
+		redact(obj)
+	}
+	c.objects[obj.GetUID()] = obj
+}
+
+// redact replaces the values of Secret obj, keeping the keys.
+func redact(obj *unstructured.Unstructured) {
+	for _, field := range []string{"data", "stringData"} {
+		values, ok, _ := unstructured.NestedMap(obj.Object, field)
+		if !ok {
+			continue
+		}
+		for key := range values {
+			values[key] = RedactedValue
+		}
+		_ = unstructured.SetNestedMap(obj.Object, values, field)
+	}
+	if annotations := obj.GetAnnotations(); annotations[lastAppliedAnnotation] != "" {
+		annotations[lastAppliedAnnotation] = RedactedValue
+		obj.SetAnnotations(annotations)
+	}
+}
+
+// fail records a failure that does not stop the collection.
+func (c *collection) fail(err error, msg string, keysAndValues ...interface{}) {
+	c.Log.Info(msg, append(keysAndValues, "error", err)...)
+	line := msg
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		line += fmt.Sprintf(" %v=%v", keysAndValues[i], keysAndValues[i+1])
+	}
+	c.errors = append(c.errors, fmt.Sprintf("%s: %v", line, err))
+}
+
+// list lists the objects of kind gvk.
+func (c *collection) list(ctx context.Context, gvk schema.GroupVersionKind, opts ...client.ListOption) ([]*unstructured.Unstructured, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := c.Client.List(ctx, list, opts...); err != nil {
+		return nil, err
+	}
+	objs := make([]*unstructured.Unstructured, len(list.Items))
+	for i := range list.Items {
+		objs[i] = &list.Items[i]
+		objs[i].SetGroupVersionKind(gvk)
+	}
+	return objs, nil
+}
+
+// objectsOfKind returns the collected objects of kind gvk.
+func (c *collection) objectsOfKind(gvk schema.GroupVersionKind) []*unstructured.Unstructured {
+	var objs []*unstructured.Unstructured
+	for _, obj := range c.objects {
+		if obj.GroupVersionKind() == gvk {
+			objs = append(objs, obj)
+		}
+	}
+	return objs
+}
+
+// objectKey returns the namespaced name of obj.
+func objectKey(obj metav1.Object) types.NamespacedName {
+	return types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+}
+
+// newObject returns an empty object of kind gvk.
+func newObject(gvk schema.GroupVersionKind) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	return obj
+}
+
+// objectPath returns the path of obj in the tarball:
+// namespaces/NAMESPACE/GROUP/KIND/NAME.yaml for a namespaced object and
+// cluster-scoped/GROUP/KIND/NAME.yaml otherwise. The group of the core API
+// is named core.
+func objectPath(obj *unstructured.Unstructured) string {
+	gvk := obj.GroupVersionKind()
+	group := gvk.Group
+	if group == "" {
+		group = "core"
+	}
+	dir := "cluster-scoped"
+	if obj.GetNamespace() != "" {
+		dir = path.Join("namespaces", obj.GetNamespace())
+	}
+	return path.Join(dir, group, strings.ToLower(gvk.Kind), obj.GetName()+".yaml")
+}
+
+// writeFile writes a regular file to tw.
+func writeFile(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	header := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0644,
+		Size:     int64(len(data)),
+		ModTime:  modTime,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
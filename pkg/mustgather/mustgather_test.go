@@ -0,0 +1,225 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mustgather
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	darkowlzzspacev1 "github.com/darkowlzz/hco/api/v1"
+)
+
+// fakeLogs returns the same log for every container.
+type fakeLogs struct{}
+
+func (fakeLogs) GetLog(ctx context.Context, namespace, pod string, opts *corev1.PodLogOptions) (io.ReadCloser, error) {
+	return ioutil.NopCloser(strings.NewReader("log of " + opts.Container)), nil
+}
+
+// ownedBy returns the metadata of an object of namespace test owned by owner.
+func ownedBy(name string, uid types.UID, kind string, owner metav1.Object) metav1.ObjectMeta {
+	meta := metav1.ObjectMeta{Namespace: "test", Name: name, UID: uid}
+	if owner != nil {
+		meta.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: "v1", Kind: kind, Name: owner.GetName(), UID: owner.GetUID(),
+		}}
+	}
+	return meta
+}
+
+// TestCollect collects a Cluster and checks the files of the tarball.
+func TestCollect(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := darkowlzzspacev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	app := &darkowlzzspacev1.App{ObjectMeta: ownedBy("app-test", "app", "", nil)}
+	cluster := &darkowlzzspacev1.Cluster{
+		ObjectMeta: ownedBy("test", "cluster", "", nil),
+		Status: darkowlzzspacev1.ClusterStatus{
+			RelatedObjects: []corev1.ObjectReference{{
+				APIVersion: darkowlzzspacev1.GroupVersion.String(), Kind: "App", Namespace: "test", Name: "app-test",
+			}},
+		},
+	}
+	deployment := &appsv1.Deployment{ObjectMeta: ownedBy("app", "deployment", "App", app)}
+	replicaSet := &appsv1.ReplicaSet{ObjectMeta: ownedBy("app-1", "replicaset", "Deployment", deployment)}
+	pod := &corev1.Pod{
+		ObjectMeta: ownedBy("app-1-a", "pod", "ReplicaSet", replicaSet),
+		Spec: corev1.PodSpec{
+			ServiceAccountName: "app",
+			Containers:         []corev1.Container{{Name: "app"}},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "app", RestartCount: 1}},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: ownedBy("app-credentials", "secret", "App", app),
+		Data:       map[string][]byte{"password": []byte("hunter2")},
+	}
+	secret.Annotations = map[string]string{lastAppliedAnnotation: `{"data":{"password":"aHVudGVyMg=="}}`}
+	event := &corev1.Event{
+		ObjectMeta:     ownedBy("app-1-a.1", "event", "", nil),
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "test", Name: "app-1-a", UID: "pod"},
+	}
+	otherEvent := &corev1.Event{
+		ObjectMeta:     ownedBy("other.1", "other-event", "", nil),
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "test", Name: "other", UID: "other"},
+	}
+	serviceAccount := &corev1.ServiceAccount{ObjectMeta: ownedBy("app", "serviceaccount", "", nil)}
+	role := &rbacv1.Role{ObjectMeta: ownedBy("app", "role", "", nil)}
+	roleBinding := &rbacv1.RoleBinding{
+		ObjectMeta: ownedBy("app", "rolebinding", "", nil),
+		Subjects:   []rbacv1.Subject{{Kind: "ServiceAccount", Name: "app"}},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: "app"},
+	}
+	otherRoleBinding := &rbacv1.RoleBinding{
+		ObjectMeta: ownedBy("other", "other-rolebinding", "", nil),
+		Subjects:   []rbacv1.Subject{{Kind: "ServiceAccount", Name: "other"}},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: "app"},
+	}
+	otherConfigMap := &corev1.ConfigMap{ObjectMeta: ownedBy("other", "other-configmap", "", nil)}
+
+	operatorPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "hco-system", Name: "hco-controller-manager-a", UID: "operator",
+			Labels: map[string]string{"control-plane": "controller-manager"},
+		},
+		Spec: corev1.PodSpec{
+			ServiceAccountName: "hco-controller-manager",
+			Containers:         []corev1.Container{{Name: "manager"}},
+		},
+	}
+	operatorServiceAccount := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "hco-system", Name: "hco-controller-manager", UID: "operator-serviceaccount",
+	}}
+	clusterRole := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "hco-manager-role", UID: "clusterrole"}}
+	clusterRoleBinding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "hco-manager-rolebinding", UID: "clusterrolebinding"},
+		Subjects: []rbacv1.Subject{{
+			Kind: "ServiceAccount", Namespace: "hco-system", Name: "hco-controller-manager",
+		}},
+		RoleRef: rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "hco-manager-role"},
+	}
+
+	c := fake.NewFakeClientWithScheme(scheme, cluster, app, deployment, replicaSet, pod, secret,
+		event, otherEvent, serviceAccount, role, roleBinding, otherRoleBinding, otherConfigMap,
+		operatorPod, operatorServiceAccount, clusterRole, clusterRoleBinding)
+	collector := &Collector{
+		Client: c,
+		Logs:   fakeLogs{},
+		Log:    log.NullLogger{},
+		Options: Options{
+			Namespace:         "test",
+			OperatorNamespace: "hco-system",
+			OperatorSelector:  labels.SelectorFromSet(labels.Set{"control-plane": "controller-manager"}),
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := collector.Collect(context.Background(), &buf, "must-gather"); err != nil {
+		t.Fatal(err)
+	}
+	files := readTarball(t, &buf)
+
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	want := []string{
+		"must-gather/cluster-scoped/rbac.authorization.k8s.io/clusterrole/hco-manager-role.yaml",
+		"must-gather/cluster-scoped/rbac.authorization.k8s.io/clusterrolebinding/hco-manager-rolebinding.yaml",
+		"must-gather/namespaces/hco-system/core/pod/hco-controller-manager-a.yaml",
+		"must-gather/namespaces/hco-system/core/serviceaccount/hco-controller-manager.yaml",
+		"must-gather/namespaces/hco-system/logs/hco-controller-manager-a/manager.log",
+		"must-gather/namespaces/test/apps/deployment/app.yaml",
+		"must-gather/namespaces/test/apps/replicaset/app-1.yaml",
+		"must-gather/namespaces/test/core/event/app-1-a.1.yaml",
+		"must-gather/namespaces/test/core/pod/app-1-a.yaml",
+		"must-gather/namespaces/test/core/secret/app-credentials.yaml",
+		"must-gather/namespaces/test/core/serviceaccount/app.yaml",
+		"must-gather/namespaces/test/darkowlzz.space/app/app-test.yaml",
+		"must-gather/namespaces/test/darkowlzz.space/cluster/test.yaml",
+		"must-gather/namespaces/test/logs/app-1-a/app.log",
+		"must-gather/namespaces/test/logs/app-1-a/app.previous.log",
+		"must-gather/namespaces/test/rbac.authorization.k8s.io/role/app.yaml",
+		"must-gather/namespaces/test/rbac.authorization.k8s.io/rolebinding/app.yaml",
+	}
+	if strings.Join(names, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected files:\n%s\nwant:\n%s", strings.Join(names, "\n"), strings.Join(want, "\n"))
+	}
+
+	data := files["must-gather/namespaces/test/core/secret/app-credentials.yaml"]
+	if bytes.Contains(data, []byte("aHVudGVyMg")) || bytes.Contains(data, []byte("hunter2")) {
+		t.Errorf("secret not redacted:\n%s", data)
+	}
+	if got := string(files["must-gather/namespaces/test/logs/app-1-a/app.log"]); got != "log of app" {
+		t.Errorf("unexpected log %q", got)
+	}
+
+	if !bytes.Contains(data, []byte("password: "+RedactedValue)) {
+		t.Errorf("expected the keys of the secret to be kept:\n%s", data)
+	}
+}
+
+// readTarball returns the regular files of a gzipped tarball, by name.
+func readTarball(t *testing.T, r io.Reader) map[string][]byte {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[header.Name] = data
+	}
+}
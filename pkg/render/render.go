@@ -0,0 +1,100 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package render renders the resources the operator produces for a Cluster,
+// without contacting the API server.
+package render
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+
+	darkowlzzspacev1 "github.com/darkowlzz/hco/api/v1"
+	"github.com/darkowlzz/hco/controllers"
+)
+
+// Cluster returns the resources the operator produces for cluster, in the
+// order they are reconciled. An invalid Cluster, which the API server would
+// reject, is an error.
+func Cluster(cluster *darkowlzzspacev1.Cluster, scheme *runtime.Scheme) ([]runtime.Object, error) {
+	if errs := cluster.Validate(); len(errs) > 0 {
+		return nil, errs.ToAggregate()
+	}
+	return controllers.RenderChildren(cluster, scheme)
+}
+
+// WriteYAML writes objs to w as a stream of YAML documents. The fields only
+// set by the API server, the creation timestamp and the status, are left
+// out.
+func WriteYAML(w io.Writer, objs []runtime.Object) error {
+	for i, obj := range objs {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return err
+		}
+		unstructured.RemoveNestedField(content, "metadata", "creationTimestamp")
+		unstructured.RemoveNestedField(content, "status")
+		data, err := yaml.Marshal(content)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			if _, err := io.WriteString(w, "---\n"); err != nil {
+				return err
+			}
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadClusters decodes the Clusters of a stream of YAML or JSON documents.
+// Empty documents are skipped, any other kind of object is an error.
+func ReadClusters(r io.Reader) ([]*darkowlzzspacev1.Cluster, error) {
+	var clusters []*darkowlzzspacev1.Cluster
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(r))
+	for i := 1; ; i++ {
+		data, err := reader.Read()
+		if err == io.EOF {
+			return clusters, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(data)) == 0 {
+			continue
+		}
+		cluster := &darkowlzzspacev1.Cluster{}
+		if err := yaml.UnmarshalStrict(data, cluster); err != nil {
+			return nil, fmt.Errorf("document %d: %w", i, err)
+		}
+		gvk := cluster.GroupVersionKind()
+		if gvk.GroupVersion() != darkowlzzspacev1.GroupVersion || gvk.Kind != "Cluster" {
+			return nil, fmt.Errorf("document %d: expected a %s Cluster, got %s %q",
+				i, darkowlzzspacev1.GroupVersion, gvk, cluster.Name)
+		}
+		clusters = append(clusters, cluster)
+	}
+}
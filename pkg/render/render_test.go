@@ -0,0 +1,114 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+
+	darkowlzzspacev1 "github.com/darkowlzz/hco/api/v1"
+)
+
+// update rewrites the golden files with the rendered resources. Run
+// "go test ./pkg/render -update" after changing the resources produced for a
+// Cluster, and review the diff of the golden files.
+var update = flag.Bool("update", false, "update the golden files")
+
+// TestCluster renders the Cluster of every testdata/*.yaml fixture and
+// compares the result with the matching testdata/*.golden file.
+func TestCluster(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := darkowlzzspacev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "*.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixtures) == 0 {
+		t.Fatal("no fixtures found")
+	}
+	for _, fixture := range fixtures {
+		fixture := fixture
+		name := strings.TrimSuffix(filepath.Base(fixture), ".yaml")
+		t.Run(name, func(t *testing.T) {
+			data, err := ioutil.ReadFile(fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var cluster darkowlzzspacev1.Cluster
+			if err := yaml.UnmarshalStrict(data, &cluster); err != nil {
+				t.Fatalf("failed to decode the fixture: %v", err)
+			}
+
+			objs, err := Cluster(&cluster, scheme)
+			if err != nil {
+				t.Fatalf("failed to render: %v", err)
+			}
+			var got bytes.Buffer
+			if err := WriteYAML(&got, objs); err != nil {
+				t.Fatalf("failed to write: %v", err)
+			}
+
+			golden := filepath.Join("testdata", name+".golden")
+			if *update {
+				if err := ioutil.WriteFile(golden, got.Bytes(), 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("failed to read the golden file, run with -update to create it: %v", err)
+			}
+			if !bytes.Equal(got.Bytes(), want) {
+				t.Errorf("rendered resources differ from %s, run with -update to accept the change\ngot:\n%s\nwant:\n%s", golden, got.Bytes(), want)
+			}
+		})
+	}
+}
+
+// TestClusterInvalid checks that an invalid Cluster is not rendered.
+func TestClusterInvalid(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := darkowlzzspacev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	cluster := &darkowlzzspacev1.Cluster{}
+	cluster.Namespace = "default"
+	cluster.Name = "Bad_Name"
+
+	objs, err := Cluster(cluster, scheme)
+	if err == nil {
+		t.Fatalf("expected an error, got %d resources", len(objs))
+	}
+	if !strings.Contains(err.Error(), "metadata.name") {
+		t.Errorf("expected an error on metadata.name, got %v", err)
+	}
+}
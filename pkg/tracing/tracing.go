@@ -0,0 +1,104 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing sets up the OpenTelemetry tracing of the manager.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpgrpc"
+	"go.opentelemetry.io/otel/exporters/stdout"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+
+	"github.com/darkowlzz/hco/pkg/version"
+)
+
+const (
+	// ExporterNone disables tracing.
+	ExporterNone = ""
+
+	// ExporterStdout writes the spans to the standard output.
+	ExporterStdout = "stdout"
+
+	// ExporterOTLP sends the spans to an OTLP collector over gRPC.
+	ExporterOTLP = "otlp"
+)
+
+// ServiceName is the service name of the spans of the manager.
+const ServiceName = "hco-controller-manager"
+
+// Options configures the tracing of the manager.
+type Options struct {
+	// Exporter is the exporter of the spans, one of ExporterNone,
+	// ExporterStdout or ExporterOTLP.
+	Exporter string
+
+	// Endpoint is the address of the OTLP collector.
+	Endpoint string
+
+	// Insecure disables the transport security of the connection to the
+	// OTLP collector.
+	Insecure bool
+
+	// SamplingRatio is the ratio of the reconciles that are traced.
+	SamplingRatio float64
+}
+
+// Setup installs the global tracer provider for the given options. The
+// returned function flushes the pending spans and stops the exporter. When
+// tracing is disabled the default no-op tracer provider is kept.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	var exporter sdktrace.SpanExporter
+	switch opts.Exporter {
+	case ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exp, err := stdout.NewExporter(stdout.WithPrettyPrint())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the stdout exporter: %w", err)
+		}
+		exporter = exp
+	case ExporterOTLP:
+		driverOpts := []otlpgrpc.Option{otlpgrpc.WithEndpoint(opts.Endpoint)}
+		if opts.Insecure {
+			driverOpts = append(driverOpts, otlpgrpc.WithInsecure())
+		}
+		exp, err := otlp.NewExporter(ctx, otlpgrpc.NewDriver(driverOpts...))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the OTLP exporter: %w", err)
+		}
+		exporter = exp
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", opts.Exporter)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SamplingRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.ServiceNameKey.String(ServiceName),
+			semconv.ServiceVersionKey.String(version.Version),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
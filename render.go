@@ -0,0 +1,89 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	darkowlzzspacev1 "github.com/darkowlzz/hco/api/v1"
+	"github.com/darkowlzz/hco/pkg/render"
+)
+
+// runRender prints the resources the operator produces for the Clusters of
+// a file, without contacting the API server. It returns the exit code.
+func runRender(args []string) int {
+	fs := newFlagSet("render", "-f FILE")
+	var file, namespace string
+	fs.StringVar(&file, "f", "", "The file containing the Clusters, - for the standard input.")
+	fs.StringVar(&namespace, "namespace", "default", "The namespace of the Clusters that do not set one.")
+	_ = fs.Parse(args)
+	if file == "" || fs.NArg() > 0 {
+		fs.Usage()
+		return 2
+	}
+
+	if err := renderFile(os.Stdout, file, namespace, scheme); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// renderFile writes the resources produced for the Clusters of file to w.
+func renderFile(w io.Writer, file, namespace string, scheme *runtime.Scheme) error {
+	clusters, err := readClusters(file, namespace)
+	if err != nil {
+		return err
+	}
+	var objs []runtime.Object
+	for _, cluster := range clusters {
+		children, err := render.Cluster(cluster, scheme)
+		if err != nil {
+			return fmt.Errorf("failed to render Cluster %s/%s: %w", cluster.Namespace, cluster.Name, err)
+		}
+		objs = append(objs, children...)
+	}
+	return render.WriteYAML(w, objs)
+}
+
+// readClusters reads the Clusters of file, the standard input when file is
+// -. The Clusters that do not set a namespace are put in namespace.
+func readClusters(file, namespace string) ([]*darkowlzzspacev1.Cluster, error) {
+	in := os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		in = f
+	}
+	clusters, err := render.ReadClusters(in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	for _, cluster := range clusters {
+		if cluster.Namespace == "" {
+			cluster.Namespace = namespace
+		}
+	}
+	return clusters, nil
+}
@@ -0,0 +1,160 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	darkowlzzspacev1 "github.com/darkowlzz/hco/api/v1"
+	"github.com/darkowlzz/hco/controllers"
+	"github.com/darkowlzz/hco/pkg/alerts"
+	"github.com/darkowlzz/hco/pkg/config"
+	"github.com/darkowlzz/hco/pkg/health"
+	"github.com/darkowlzz/hco/pkg/tracing"
+)
+
+// runManager runs the controller manager, along with the admission webhooks
+// when they are enabled.
+func runManager(args []string) int {
+	fs := newFlagSet("run", "[FLAGS]")
+	cfg := config.Default()
+	cfg.BindFlags(fs)
+	logLevel, err := parseServerFlags(fs, cfg, args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		Exporter:      cfg.Tracing.Exporter,
+		Endpoint:      cfg.Tracing.Endpoint,
+		Insecure:      cfg.Tracing.Insecure,
+		SamplingRatio: cfg.Tracing.SamplingRatio,
+	})
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		return 1
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			setupLog.Error(err, "unable to flush the traces")
+		}
+	}()
+
+	options := managerOptions(cfg)
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), options)
+	if err != nil {
+		setupLog.Error(err, "unable to start manager")
+		return 1
+	}
+
+	if err = (&controllers.ClusterReconciler{
+		Client:          mgr.GetClient(),
+		Log:             ctrl.Log.WithName("controllers").WithName("Cluster"),
+		Scheme:          mgr.GetScheme(),
+		WatchNamespaces: cfg.WatchNamespaces,
+	}).SetupWithManager(mgr,
+		cfg.ControllerOptions(cfg.Controllers.Cluster),
+		cfg.ControllerOptions(cfg.Controllers.Cluster)); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Cluster")
+		return 1
+	}
+	if err = (&controllers.AppReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("App"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr, cfg.ControllerOptions(cfg.Controllers.App)); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "App")
+		return 1
+	}
+	if err = (&controllers.SidecarAReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("SidecarA"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr, cfg.ControllerOptions(cfg.Controllers.SidecarA)); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SidecarA")
+		return 1
+	}
+	if err = (&controllers.SidecarBReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("SidecarB"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr, cfg.ControllerOptions(cfg.Controllers.SidecarB)); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SidecarB")
+		return 1
+	}
+	if cfg.Webhook.Enabled {
+		if err = (&darkowlzzspacev1.Cluster{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Cluster")
+			return 1
+		}
+	}
+	// +kubebuilder:scaffold:builder
+
+	alertOptions := alerts.Options{
+		Enabled:              cfg.Alerts.Enabled,
+		Namespace:            cfg.Alerts.Namespace,
+		Disabled:             cfg.Alerts.Disabled,
+		DegradedFor:          cfg.Alerts.DegradedFor.Duration,
+		UpgradeStuckFor:      cfg.Alerts.UpgradeStuckFor.Duration,
+		ComponentNotReadyFor: cfg.Alerts.ComponentNotReadyFor.Duration,
+		CrashLoopingFor:      cfg.Alerts.CrashLoopingFor.Duration,
+	}
+	if alertOptions.Namespace == "" {
+		setupLog.Info("no namespace set for the PrometheusRule, alerts are not maintained")
+	} else if err := mgr.Add(&alerts.RuleReconciler{
+		Client:       mgr.GetClient(),
+		Log:          ctrl.Log.WithName("alerts"),
+		Options:      alertOptions,
+		FieldManager: controllers.FieldManager,
+	}); err != nil {
+		setupLog.Error(err, "unable to set up alerts")
+		return 1
+	}
+
+	if err := addServerEndpoints(mgr, cfg, logLevel); err != nil {
+		setupLog.Error(err, "unable to set up the manager endpoints")
+		return 1
+	}
+	if cfg.LeaderElection.LeaderElect {
+		leaderElection := health.NewLeaderElection(mgr.GetAPIReader(), ctrl.Log.WithName("leader-election"),
+			options.LeaderElectionNamespace, options.LeaderElectionID)
+		if err := mgr.Add(leaderElection); err != nil {
+			setupLog.Error(err, "unable to track leader election")
+			return 1
+		}
+		if err := mgr.Add(leaderElection.Monitor(cfg.LeaderElection.LeaseDuration.Duration)); err != nil {
+			setupLog.Error(err, "unable to monitor leader election")
+			return 1
+		}
+		if err := mgr.AddReadyzCheck("leader-election", leaderElection.Check); err != nil {
+			setupLog.Error(err, "unable to set up ready check", "check", "leader-election")
+			return 1
+		}
+	}
+
+	setupLog.Info("starting manager")
+	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+		setupLog.Error(err, "problem running manager")
+		return 1
+	}
+	return 0
+}
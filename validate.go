@@ -0,0 +1,69 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// runValidate checks the Clusters of a file against the rules of the API,
+// without contacting the API server.
+func runValidate(args []string) int {
+	fs := newFlagSet("validate", "-f FILE")
+	var file, namespace string
+	fs.StringVar(&file, "f", "", "The file containing the Clusters, - for the standard input.")
+	fs.StringVar(&namespace, "namespace", "default", "The namespace of the Clusters that do not set one.")
+	_ = fs.Parse(args)
+	if file == "" || fs.NArg() > 0 {
+		fs.Usage()
+		return 2
+	}
+
+	clusters, err := readClusters(file, namespace)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	invalid := false
+	primaries := map[string]string{}
+	for _, cluster := range clusters {
+		key := types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}
+		errs := cluster.Validate()
+		// Only one Cluster is allowed per namespace.
+		if primary, ok := primaries[cluster.Namespace]; ok && primary != cluster.Name {
+			errs = append(errs, field.Forbidden(field.NewPath("metadata", "namespace"),
+				fmt.Sprintf("Cluster %q is already in namespace %q, only one Cluster is allowed per namespace", primary, cluster.Namespace)))
+		} else {
+			primaries[cluster.Namespace] = cluster.Name
+		}
+		if len(errs) > 0 {
+			invalid = true
+			fmt.Fprintf(os.Stderr, "Cluster %s is invalid: %v\n", key, errs.ToAggregate())
+			continue
+		}
+		fmt.Printf("Cluster %s is valid\n", key)
+	}
+	if invalid {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,46 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/darkowlzz/hco/pkg/version"
+)
+
+// runVersion prints the build information of the operator.
+func runVersion(args []string) int {
+	fs := newFlagSet("version", "[FLAGS]")
+	short := fs.Bool("short", false, "Print the version only.")
+	_ = fs.Parse(args)
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return 2
+	}
+
+	info := version.Get()
+	if *short {
+		fmt.Println(info.Version)
+		return 0
+	}
+	fmt.Printf("Version:    %s\n", info.Version)
+	fmt.Printf("Git commit: %s\n", info.Commit)
+	fmt.Printf("Build date: %s\n", info.BuildDate)
+	fmt.Printf("Go version: %s\n", info.GoVersion)
+	fmt.Printf("Platform:   %s\n", info.Platform)
+	return 0
+}
@@ -0,0 +1,64 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	darkowlzzspacev1 "github.com/darkowlzz/hco/api/v1"
+	"github.com/darkowlzz/hco/pkg/config"
+)
+
+// runWebhook runs a manager serving the admission webhooks only, without
+// the controllers. The webhooks are served by every replica, leader election
+// is not used.
+func runWebhook(args []string) int {
+	fs := newFlagSet("webhook", "[FLAGS]")
+	cfg := config.Default()
+	cfg.BindServerFlags(fs)
+	logLevel, err := parseServerFlags(fs, cfg, args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	cfg.Webhook.Enabled = true
+	cfg.LeaderElection.LeaderElect = false
+
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), managerOptions(cfg))
+	if err != nil {
+		setupLog.Error(err, "unable to start manager")
+		return 1
+	}
+	if err = (&darkowlzzspacev1.Cluster{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "Cluster")
+		return 1
+	}
+	if err := addServerEndpoints(mgr, cfg, logLevel); err != nil {
+		setupLog.Error(err, "unable to set up the manager endpoints")
+		return 1
+	}
+
+	setupLog.Info("starting webhook server")
+	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+		setupLog.Error(err, "problem running webhook server")
+		return 1
+	}
+	return 0
+}